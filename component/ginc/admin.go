@@ -0,0 +1,200 @@
+package ginc
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sctx "github.com/DatLe328/service-context"
+	"github.com/DatLe328/service-context/core"
+	"github.com/DatLe328/service-context/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Admin routes, opt-in with gin-admin-enabled
+	Every request must send "Authorization: Bearer <gin-admin-token>"
+
+	GET {gin-admin-path}/log-level
+	PUT {gin-admin-path}/log-level {"level": "debug", "prefix": "gorm", "duration": "10m"}
+		prefix is optional, empty means the global level
+		duration is optional, the previous level is restored once it elapses
+*/
+
+type logLevelRequest struct {
+	Level    string `json:"level" binding:"required"`
+	Prefix   string `json:"prefix"`
+	Duration string `json:"duration"`
+}
+
+type logLevelResponse struct {
+	Level    string            `json:"level"`
+	Prefixes map[string]string `json:"prefixes"`
+	Reverts  map[string]string `json:"reverts,omitempty"`
+}
+
+type pendingRevert struct {
+	timer    *time.Timer
+	previous string
+	at       time.Time
+}
+
+type logLevelAdmin struct {
+	serviceCtx sctx.ServiceContext
+	logger     logger.Logger
+	mu         sync.Mutex
+	reverts    map[string]*pendingRevert
+}
+
+func newLogLevelAdmin(serviceCtx sctx.ServiceContext, logger logger.Logger) *logLevelAdmin {
+	return &logLevelAdmin{
+		serviceCtx: serviceCtx,
+		logger:     logger,
+		reverts:    make(map[string]*pendingRevert),
+	}
+}
+
+func (g *ginEngine) registerAdminRoutes(serviceCtx sctx.ServiceContext) {
+	g.admin = newLogLevelAdmin(serviceCtx, g.logger)
+
+	group := g.router.Group(g.adminPath, requireAdminToken(g.adminToken))
+	group.GET("/log-level", g.admin.getLevel)
+	group.PUT("/log-level", g.admin.setLevel)
+}
+
+func requireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			err := core.ErrUnauthorized(errors.New("invalid admin token"), "", "ErrUnauthorized")
+			c.AbortWithStatusJSON(err.StatusCode(), err)
+			return
+		}
+		c.Next()
+	}
+}
+
+func (a *logLevelAdmin) getLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, core.ResponseData(a.state()))
+}
+
+func (a *logLevelAdmin) setLevel(c *gin.Context) {
+	var req logLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, core.ErrInvalidRequest(err))
+		return
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d < 0 {
+			c.JSON(http.StatusBadRequest, core.ErrBadRequest(errors.New("invalid duration"), "Invalid duration"))
+			return
+		}
+		duration = d
+	}
+
+	if err := a.set(req.Prefix, req.Level, duration); err != nil {
+		c.JSON(http.StatusBadRequest, core.ErrBadRequest(err, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, core.ResponseData(a.state()))
+}
+
+func (a *logLevelAdmin) set(prefix, level string, duration time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// keep the level from before the first temporary change
+	previous := a.currentLevel(prefix)
+	if pending, ok := a.reverts[prefix]; ok {
+		previous = pending.previous
+	}
+
+	if err := a.serviceCtx.SetLogLevel(prefix, level); err != nil {
+		return err
+	}
+
+	if pending, ok := a.reverts[prefix]; ok {
+		pending.timer.Stop()
+		delete(a.reverts, prefix)
+	}
+
+	a.logger.Warnf("log level of %q set to %s (duration=%s)", prefix, level, duration)
+
+	if duration > 0 {
+		a.reverts[prefix] = &pendingRevert{
+			timer:    time.AfterFunc(duration, func() { a.revert(prefix) }),
+			previous: previous,
+			at:       time.Now().Add(duration),
+		}
+	}
+
+	return nil
+}
+
+func (a *logLevelAdmin) revert(prefix string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pending, ok := a.reverts[prefix]
+	if !ok {
+		return
+	}
+	delete(a.reverts, prefix)
+
+	var err error
+	if pending.previous == "" {
+		err = a.serviceCtx.ResetLogLevel(prefix)
+	} else {
+		err = a.serviceCtx.SetLogLevel(prefix, pending.previous)
+	}
+
+	if err != nil {
+		a.logger.Errorf("revert log level of %q: %v", prefix, err)
+		return
+	}
+	a.logger.Warnf("log level of %q reverted", prefix)
+}
+
+func (a *logLevelAdmin) currentLevel(prefix string) string {
+	if prefix == "" {
+		return a.serviceCtx.LogLevel()
+	}
+	return a.serviceCtx.PrefixLogLevels()[prefix]
+}
+
+func (a *logLevelAdmin) state() logLevelResponse {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	resp := logLevelResponse{
+		Level:    a.serviceCtx.LogLevel(),
+		Prefixes: a.serviceCtx.PrefixLogLevels(),
+	}
+
+	if len(a.reverts) > 0 {
+		resp.Reverts = make(map[string]string, len(a.reverts))
+		for prefix, pending := range a.reverts {
+			resp.Reverts[prefix] = pending.at.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return resp
+}
+
+// stop cancels the pending reverts.
+func (a *logLevelAdmin) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for prefix, pending := range a.reverts {
+		pending.timer.Stop()
+		delete(a.reverts, prefix)
+	}
+}
@@ -1,29 +1,81 @@
 package ginc
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	sctx "github.com/DatLe328/service-context"
+	"github.com/DatLe328/service-context/component/ginc/middleware"
+	"github.com/DatLe328/service-context/internal/flagutil"
 	"github.com/DatLe328/service-context/logger"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	defaultPort = 3000
-	defaultMode = "debug"
+	defaultPort              = 3000
+	defaultMode              = "debug"
+	defaultReadTimeout       = 30 * time.Second
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultShutdownTimeout   = 15 * time.Second
+	defaultAdminPath         = "/admin"
+	defaultCORSMethods       = "GET,POST,PUT,PATCH,DELETE,HEAD,OPTIONS"
+	defaultCORSMaxAge        = 12 * time.Hour
 )
 
 type Config struct {
-	port    int
-	ginMode string
+	host              string
+	port              int
+	ginMode           string
+	readTimeout       time.Duration
+	readHeaderTimeout time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	maxHeaderBytes    int
+	shutdownTimeout   time.Duration
+	shutdownDelay     time.Duration
+	healthEnabled     bool
+	adminEnabled      bool
+	adminPath         string
+	adminToken        string
+	corsEnabled       bool
+	corsOrigins       string
+	corsMethods       string
+	corsHeaders       string
+	corsExposeHeaders string
+	corsCredentials   bool
+	corsMaxAge        time.Duration
 }
 
+type GINComponent interface {
+	GetAddr() string
+	GetPort() int
+	GetRouter() *gin.Engine
+	GetServer() *http.Server
+}
+
+var _ GINComponent = (*ginEngine)(nil)
+
 type ginEngine struct {
 	*Config
-	id     string
-	logger logger.Logger
-	router *gin.Engine
+	id           string
+	logger       logger.Logger
+	router       *gin.Engine
+	server       *http.Server
+	admin        *logLevelAdmin
+	draining     atomic.Bool
+	shutdownOnce sync.Once
+	shutdownErr  error
 }
 
 func NewGin(id string) *ginEngine {
@@ -37,6 +89,36 @@ func (g *ginEngine) ID() string {
 	return g.id
 }
 
+func (g *ginEngine) Validate() error {
+	var errs []error
+
+	switch g.ginMode {
+	case "", gin.DebugMode, gin.ReleaseMode:
+	default:
+		errs = append(errs, fmt.Errorf("invalid gin mode: %s (allowed: debug | release)", g.ginMode))
+	}
+
+	if g.adminEnabled && g.adminToken == "" {
+		errs = append(errs, errors.New("gin admin token is required when admin routes are enabled"))
+	}
+
+	if g.corsEnabled {
+		origins := flagutil.SplitList(g.corsOrigins)
+		if len(origins) == 0 {
+			errs = append(errs, errors.New("gin cors allowed origins are required when cors is enabled"))
+		}
+		if g.corsCredentials && slices.Contains(origins, "*") {
+			errs = append(errs, errors.New("gin cors credentials cannot be allowed for the * origin"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *ginEngine) SensitiveFlags() []string {
+	return []string{"gin-admin-token"}
+}
+
 func (g *ginEngine) Activate(serviceContext sctx.ServiceContext) error {
 	// gin-mode > app-env
 	env := serviceContext.EnvName()
@@ -47,13 +129,7 @@ func (g *ginEngine) Activate(serviceContext sctx.ServiceContext) error {
 	}
 
 	if g.ginMode != "" {
-		switch g.ginMode {
-		case gin.DebugMode, gin.ReleaseMode:
-			mode = g.ginMode
-
-		default:
-			return fmt.Errorf("invalid gin mode: %s (allowed: debug | release)", g.ginMode)
-		}
+		mode = g.ginMode
 	}
 
 	gin.SetMode(mode)
@@ -62,16 +138,130 @@ func (g *ginEngine) Activate(serviceContext sctx.ServiceContext) error {
 	g.logger.Info("init engine...")
 	g.router = gin.New()
 
+	if g.corsEnabled {
+		g.router.Use(middleware.CORS(g.corsConfig()))
+	}
+
+	if g.healthEnabled {
+		g.registerHealthRoutes(serviceContext)
+	}
+
+	if g.adminEnabled {
+		g.registerAdminRoutes(serviceContext)
+	}
+
+	g.server = &http.Server{
+		Addr:              g.GetAddr(),
+		Handler:           g.router,
+		ReadTimeout:       g.readTimeout,
+		ReadHeaderTimeout: g.readHeaderTimeout,
+		WriteTimeout:      g.writeTimeout,
+		IdleTimeout:       g.idleTimeout,
+		MaxHeaderBytes:    g.maxHeaderBytes,
+	}
+
 	return nil
 }
 
 func (g *ginEngine) Stop() error {
-	return nil
+	return g.StopContext(context.Background())
 }
 
-func (g *ginEngine) InitFlags() {
-	flag.IntVar(&g.port, "gin-port", defaultPort, "gin server port. Default 3000")
-	flag.StringVar(&g.ginMode, "gin-mode", defaultMode, "gin server (debug | release). Default debug")
+// StopContext gracefully shuts the server down, giving in-flight requests up
+// to gin-shutdown-timeout to complete.
+func (g *ginEngine) StopContext(ctx context.Context) error {
+	if g.admin != nil {
+		g.admin.stop()
+	}
+
+	if g.server == nil {
+		return nil
+	}
+
+	return g.shutdown(ctx)
+}
+
+func (g *ginEngine) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- g.server.ListenAndServe()
+	}()
+
+	g.logger.Infof("listening on %s", g.server.Addr)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	return g.StopContext(context.Background())
+}
+
+// shutdown fails /readyz for gin-shutdown-delay, so that load balancers stop
+// sending traffic, then closes the listeners and waits for in-flight requests.
+func (g *ginEngine) shutdown(ctx context.Context) error {
+	g.shutdownOnce.Do(func() {
+		g.draining.Store(true)
+
+		if g.shutdownDelay > 0 {
+			g.logger.Infof("draining for %s before shutting down", g.shutdownDelay)
+			select {
+			case <-time.After(g.shutdownDelay):
+			case <-ctx.Done():
+			}
+		}
+
+		g.logger.Info("shutting down server...")
+
+		ctx, cancel := context.WithTimeout(ctx, g.shutdownTimeout)
+		defer cancel()
+
+		g.shutdownErr = g.server.Shutdown(ctx)
+	})
+	return g.shutdownErr
+}
+
+func (g *ginEngine) InitFlags(fs *flag.FlagSet) {
+	fs.StringVar(&g.host, "gin-host", "", "gin server host. Default all interfaces")
+	fs.IntVar(&g.port, "gin-port", defaultPort, "gin server port. Default 3000")
+	fs.StringVar(&g.ginMode, "gin-mode", defaultMode, "gin server (debug | release). Default debug")
+	fs.DurationVar(&g.readTimeout, "gin-read-timeout", defaultReadTimeout, "maximum duration for reading the entire request. Default 30s")
+	fs.DurationVar(&g.readHeaderTimeout, "gin-read-header-timeout", defaultReadHeaderTimeout, "maximum duration for reading request headers. Default 10s")
+	fs.DurationVar(&g.writeTimeout, "gin-write-timeout", defaultWriteTimeout, "maximum duration before timing out writes of the response. Default 30s")
+	fs.DurationVar(&g.idleTimeout, "gin-idle-timeout", defaultIdleTimeout, "maximum time to wait for the next request on keep-alive connections. Default 120s")
+	fs.IntVar(&g.maxHeaderBytes, "gin-max-header-bytes", http.DefaultMaxHeaderBytes, "maximum size of request headers in bytes. Default 1MB")
+	fs.DurationVar(&g.shutdownTimeout, "gin-shutdown-timeout", defaultShutdownTimeout, "time to drain in-flight requests on shutdown. Default 15s")
+	fs.DurationVar(&g.shutdownDelay, "gin-shutdown-delay", 0, "time /readyz fails before the server stops accepting requests on shutdown. Default 0")
+	fs.BoolVar(&g.healthEnabled, "gin-health-enabled", false, "serve /livez, /healthz and /readyz probes. Default false")
+	fs.BoolVar(&g.adminEnabled, "gin-admin-enabled", false, "serve admin routes (runtime log level). Default false")
+	fs.StringVar(&g.adminPath, "gin-admin-path", defaultAdminPath, "base path of admin routes. Default /admin")
+	fs.StringVar(&g.adminToken, "gin-admin-token", "", "bearer token required by admin routes")
+	fs.BoolVar(&g.corsEnabled, "gin-cors-enabled", false, "answer CORS requests. Default false")
+	fs.StringVar(&g.corsOrigins, "gin-cors-allowed-origins", "", "comma-separated allowed origins: *, https://app.example.com or https://*.example.com")
+	fs.StringVar(&g.corsMethods, "gin-cors-allowed-methods", defaultCORSMethods, "comma-separated methods allowed by CORS preflight")
+	fs.StringVar(&g.corsHeaders, "gin-cors-allowed-headers", "", "comma-separated headers allowed by CORS preflight. Default the requested ones")
+	fs.StringVar(&g.corsExposeHeaders, "gin-cors-exposed-headers", middleware.HeaderRequestID, "comma-separated response headers exposed to browsers")
+	fs.BoolVar(&g.corsCredentials, "gin-cors-allow-credentials", false, "allow cookies and authorization headers in CORS requests. Default false")
+	fs.DurationVar(&g.corsMaxAge, "gin-cors-max-age", defaultCORSMaxAge, "how long browsers cache CORS preflight results. Default 12h")
+}
+
+func (g *ginEngine) corsConfig() middleware.CORSConfig {
+	return middleware.CORSConfig{
+		AllowOrigins:     flagutil.SplitList(g.corsOrigins),
+		AllowMethods:     flagutil.SplitList(g.corsMethods),
+		AllowHeaders:     flagutil.SplitList(g.corsHeaders),
+		ExposeHeaders:    flagutil.SplitList(g.corsExposeHeaders),
+		AllowCredentials: g.corsCredentials,
+		MaxAge:           g.corsMaxAge,
+	}
+}
+
+func (g *ginEngine) GetAddr() string {
+	return net.JoinHostPort(g.host, strconv.Itoa(g.port))
 }
 
 func (g *ginEngine) GetPort() int {
@@ -81,3 +271,7 @@ func (g *ginEngine) GetPort() int {
 func (g *ginEngine) GetRouter() *gin.Engine {
 	return g.router
 }
+
+func (g *ginEngine) GetServer() *http.Server {
+	return g.server
+}
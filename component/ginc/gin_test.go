@@ -1,17 +1,29 @@
 package ginc
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	sctx "github.com/DatLe328/service-context"
+	"github.com/gin-gonic/gin"
 )
 
+const testAdminToken = "test-admin-token"
+
 var testServiceCtx sctx.ServiceContext
 
 func TestMain(m *testing.M) {
 	_ = os.Setenv("GIN_PORT", "4000")
 	_ = os.Setenv("GIN_MODE", "debug")
+	_ = os.Setenv("GIN_HEALTH_ENABLED", "true")
+	_ = os.Setenv("GIN_ADMIN_ENABLED", "true")
+	_ = os.Setenv("GIN_ADMIN_TOKEN", testAdminToken)
 
 	testServiceCtx = sctx.NewServiceContext(
 		sctx.WithName("test"),
@@ -34,3 +46,179 @@ func TestGin_Activate(t *testing.T) {
 		t.Fatal("gin router should not be nil")
 	}
 }
+
+func TestGin_HealthRoutes(t *testing.T) {
+	g := testServiceCtx.MustGet("gin").(*ginEngine)
+
+	for _, path := range []string{"/livez", "/healthz", "/readyz"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		g.GetRouter().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d (%s)", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func adminRequest(t *testing.T, method, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	g := testServiceCtx.MustGet("gin").(*ginEngine)
+
+	req := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	g.GetRouter().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGin_Admin_RequiresToken(t *testing.T) {
+	for _, token := range []string{"", "wrong-token"} {
+		if rec := adminRequest(t, http.MethodGet, "", token); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("token %q: expected 401, got %d", token, rec.Code)
+		}
+	}
+}
+
+func TestGin_Admin_SetLogLevelWithRevert(t *testing.T) {
+	previous := testServiceCtx.LogLevel()
+
+	rec := adminRequest(t, http.MethodPut, `{"level": "error", "duration": "50ms"}`, testAdminToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	if level := testServiceCtx.LogLevel(); level != "error" {
+		t.Fatalf("expected level error, got %s", level)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if level := testServiceCtx.LogLevel(); level != previous {
+		t.Fatalf("expected level to revert to %s, got %s", previous, level)
+	}
+
+	rec = adminRequest(t, http.MethodPut, `{"level": "loud"}`, testAdminToken)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid level: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestGin_Validate_CORS(t *testing.T) {
+	g := NewGin("gin")
+	g.corsEnabled = true
+
+	if err := g.Validate(); err == nil {
+		t.Fatal("expected error without allowed origins")
+	}
+
+	g.corsOrigins = "*"
+	g.corsCredentials = true
+	if err := g.Validate(); err == nil {
+		t.Fatal("expected error for credentials with * origin")
+	}
+
+	g.corsOrigins = "https://app.example.com, https://*.example.org"
+	if err := g.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGin_Shutdown_FailsReadinessDuringDelay(t *testing.T) {
+	g := NewGin("probe")
+	serviceCtx := sctx.NewServiceContext(
+		sctx.WithName("probe"),
+		sctx.WithArgs("-gin-port", "0", "-gin-health-enabled", "-gin-shutdown-delay", "200ms"),
+		sctx.WithComponent(g),
+	)
+	if err := serviceCtx.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	readyz := func() int {
+		rec := httptest.NewRecorder()
+		g.GetRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+
+	if code := readyz(); code != http.StatusOK {
+		t.Fatalf("expected 200 before shutdown, got %d", code)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- serviceCtx.Stop() }()
+
+	time.Sleep(50 * time.Millisecond)
+	if code := readyz(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while draining, got %d", code)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGin_Run_DrainsInFlightRequests(t *testing.T) {
+	g := testServiceCtx.MustGet("gin").(*ginEngine)
+
+	started := make(chan struct{})
+	g.GetRouter().GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- g.Run(ctx)
+	}()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/slow", g.GetPort())
+
+	type result struct {
+		status int
+		err    error
+	}
+	resCh := make(chan result, 1)
+
+	go func() {
+		for i := 0; i < 50; i++ {
+			resp, err := http.Get(url)
+			if err != nil {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			_ = resp.Body.Close()
+			resCh <- result{status: resp.StatusCode}
+			return
+		}
+		resCh <- result{err: fmt.Errorf("server did not start listening")}
+	}()
+
+	select {
+	case <-started:
+	case res := <-resCh:
+		t.Fatalf("request finished before reaching handler: %+v", res)
+	}
+
+	cancel()
+
+	res := <-resCh
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+
+	if res.status != http.StatusOK {
+		t.Fatalf("in-flight request should complete, got status %d", res.status)
+	}
+
+	if err := <-runErr; err != nil {
+		t.Fatal(err)
+	}
+}
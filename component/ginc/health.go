@@ -0,0 +1,41 @@
+package ginc
+
+import (
+	"net/http"
+
+	sctx "github.com/DatLe328/service-context"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Kubernetes probes, opt-in with gin-health-enabled
+	/livez   - process is up, never checks dependencies
+	/healthz - aggregated health report of all components
+	/readyz  - same report, but also fails during gin-shutdown-delay on shutdown
+*/
+
+func (g *ginEngine) registerHealthRoutes(serviceCtx sctx.ServiceContext) {
+	g.router.GET("/livez", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": sctx.HealthStatusUp})
+	})
+
+	g.router.GET("/healthz", func(c *gin.Context) {
+		writeHealthReport(c, serviceCtx.Health(c.Request.Context()))
+	})
+
+	g.router.GET("/readyz", func(c *gin.Context) {
+		if g.draining.Load() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": sctx.HealthStatusDown, "reason": "shutting down"})
+			return
+		}
+		writeHealthReport(c, serviceCtx.Health(c.Request.Context()))
+	})
+}
+
+func writeHealthReport(c *gin.Context, report sctx.HealthReport) {
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
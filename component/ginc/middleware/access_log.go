@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	sctx "github.com/DatLe328/service-context"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Log one structured entry per request once it is served
+	5xx are logged as errors, 4xx as warnings, the rest as info
+	skipPaths are not logged, e.g. health probes
+*/
+
+func AccessLog(serviceCtx sctx.ServiceContext, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	log := serviceCtx.Logger("access")
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		if skip[path] {
+			return
+		}
+
+		status := c.Writer.Status()
+		kv := []interface{}{
+			"status", status,
+			"method", c.Request.Method,
+			"path", path,
+			"query", c.Request.URL.RawQuery,
+			"latency", time.Since(start).String(),
+			"bytes", max(c.Writer.Size(), 0), // -1 without body
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+
+		if requestID := GetRequestID(c); requestID != "" {
+			kv = append(kv, KeyRequestID, requestID)
+		}
+
+		if requester := requesterOf(c); requester != nil {
+			kv = append(kv, "requester", requester.GetSubject())
+		}
+
+		if len(c.Errors) > 0 {
+			kv = append(kv, "errors", c.Errors.String())
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Errorw("request", kv...)
+		case status >= http.StatusBadRequest:
+			log.Warnw("request", kv...)
+		default:
+			log.Infow("request", kv...)
+		}
+	}
+}
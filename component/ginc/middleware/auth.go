@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/DatLe328/service-context/component/jwtc"
+	"github.com/DatLe328/service-context/core"
+	"github.com/DatLe328/service-context/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Authenticate requests with a JWT issued by jwtc
+	The token is read from the first source returning one, by default "Authorization: Bearer <token>"
+	On success the core.Requester (subject, token id, roles, scopes) is stored in gin.Context under core.KeyRequester
+	and in the request context, read it with core.GetRequester(c.Request.Context())
+	RequireAuth answers core.ErrUnauthorized without token and core.ErrInvalidToken for a bad one
+	OptionalAuth lets requests without token through, but still rejects bad tokens
+*/
+
+// TokenSource extracts the raw token of a request, "" when there is none.
+type TokenSource func(c *gin.Context) string
+
+// HeaderToken reads the token from header, stripping a "Bearer" scheme. A
+// value without scheme is the token itself. Other schemes, e.g. Basic
+// credentials, and a scheme with nothing after it yield no token.
+func HeaderToken(header string) TokenSource {
+	return func(c *gin.Context) string {
+		value := strings.TrimSpace(c.GetHeader(header))
+
+		scheme, token, hasScheme := strings.Cut(value, " ")
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			return strings.TrimSpace(token)
+		case hasScheme:
+			return ""
+		default:
+			return value
+		}
+	}
+}
+
+func CookieToken(name string) TokenSource {
+	return func(c *gin.Context) string {
+		value, _ := c.Cookie(name)
+		return value
+	}
+}
+
+func QueryToken(param string) TokenSource {
+	return func(c *gin.Context) string {
+		return c.Query(param)
+	}
+}
+
+type AuthOption func(*authConfig)
+
+// WithTokenSources replaces the default Authorization header source. Sources
+// are tried in order.
+func WithTokenSources(sources ...TokenSource) AuthOption {
+	return func(cfg *authConfig) {
+		cfg.sources = sources
+	}
+}
+
+type authConfig struct {
+	sources []TokenSource
+}
+
+func RequireAuth(tp jwtc.TokenProvider, opts ...AuthOption) gin.HandlerFunc {
+	return authenticate(tp, true, opts)
+}
+
+func OptionalAuth(tp jwtc.TokenProvider, opts ...AuthOption) gin.HandlerFunc {
+	return authenticate(tp, false, opts)
+}
+
+func authenticate(tp jwtc.TokenProvider, required bool, opts []AuthOption) gin.HandlerFunc {
+	cfg := &authConfig{
+		sources: []TokenSource{HeaderToken("Authorization")},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(c *gin.Context) {
+		token := extractToken(c, cfg.sources)
+		if token == "" {
+			if !required {
+				c.Next()
+				return
+			}
+
+			err := core.ErrUnauthorized(errors.New("missing access token"), "", "ErrUnauthorized")
+			c.AbortWithStatusJSON(err.StatusCode(), err)
+			return
+		}
+
+		requester, err := parseRequester(c, tp, token)
+		if err != nil {
+			appErr := core.ErrInvalidToken(err)
+			c.AbortWithStatusJSON(appErr.StatusCode(), appErr)
+			return
+		}
+
+		setRequester(c, requester)
+
+		c.Next()
+	}
+}
+
+// parseRequester reads roles and scopes too when tp supports them.
+func parseRequester(c *gin.Context, tp jwtc.TokenProvider, token string) (core.Requester, error) {
+	if cp, ok := tp.(jwtc.ClaimsProvider); ok {
+		claims, err := cp.ParseClaims(c.Request.Context(), token)
+		if err != nil {
+			return nil, err
+		}
+
+		return core.NewRequester(claims.Subject, claims.ID).
+			WithRoles(claims.Roles...).
+			WithScopes(claims.Scopes...), nil
+	}
+
+	claims, err := tp.ParseToken(c.Request.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.NewRequester(claims.Subject, claims.ID), nil
+}
+
+func extractToken(c *gin.Context, sources []TokenSource) string {
+	for _, source := range sources {
+		if token := source(c); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// setRequester stores requester for handlers and adds its subject to the
+// request logging fields.
+func setRequester(c *gin.Context, requester core.Requester) {
+	c.Set(core.KeyRequester, requester)
+
+	ctx := core.ContextWithRequester(c.Request.Context(), requester)
+	ctx = logger.ContextWithFields(ctx, logger.Fields{"requester": requester.GetSubject()})
+	c.Request = c.Request.WithContext(ctx)
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DatLe328/service-context/core"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Authorize the requester set by RequireAuth or OptionalAuth
+	RequireRoles passes when the requester has at least one of the roles
+	RequireScopes passes when the requester has every scope
+	Answer core.ErrUnauthorized without requester and core.ErrNoPermission otherwise
+	Both panic without roles or scopes, which would reject or let everyone through
+*/
+
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	if len(roles) == 0 {
+		panic("middleware.RequireRoles: at least one role is required")
+	}
+
+	return authorize(func(r core.Requester) error {
+		for _, role := range roles {
+			if core.HasRole(r, role) {
+				return nil
+			}
+		}
+		return fmt.Errorf("requester needs one of the roles: %s", strings.Join(roles, ", "))
+	})
+}
+
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	if len(scopes) == 0 {
+		panic("middleware.RequireScopes: at least one scope is required")
+	}
+
+	return authorize(func(r core.Requester) error {
+		var missing []string
+		for _, scope := range scopes {
+			if !core.HasScope(r, scope) {
+				missing = append(missing, scope)
+			}
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("requester misses the scopes: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	})
+}
+
+func authorize(check func(core.Requester) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requester := requesterOf(c)
+		if requester == nil {
+			err := core.ErrUnauthorized(errors.New("missing requester"), "", "ErrUnauthorized")
+			c.AbortWithStatusJSON(err.StatusCode(), err)
+			return
+		}
+
+		if err := check(requester); err != nil {
+			appErr := core.ErrNoPermission(err)
+			c.AbortWithStatusJSON(appErr.StatusCode(), appErr)
+			return
+		}
+
+		c.Next()
+	}
+}
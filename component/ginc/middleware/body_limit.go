@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/DatLe328/service-context/core"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Reject request bodies larger than maxBytes with core.ErrPayloadTooLarge
+	Bodies announcing a larger Content-Length are rejected before the handler runs,
+	the others fail to read past the limit: what the handler answers then, e.g. a 400
+	from a failed binding, is dropped and replaced by the 413
+	Only a response already sent before reading past the limit is kept
+*/
+
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			abortPayloadTooLarge(c, maxBytes)
+			return
+		}
+
+		writer := c.Writer
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(writer, c.Request.Body, maxBytes)}
+		c.Request.Body = body
+		c.Writer = &limitedWriter{ResponseWriter: writer, body: body}
+
+		c.Next()
+
+		c.Writer = writer
+		if body.exceeded && !writer.Written() {
+			abortPayloadTooLarge(c, maxBytes)
+		}
+	}
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// limitedWriter discards the response of the handler once the body limit is
+// exceeded, so that BodyLimit can answer 413 instead.
+type limitedWriter struct {
+	gin.ResponseWriter
+	body *limitedBody
+}
+
+func (w *limitedWriter) WriteHeader(code int) {
+	if !w.body.exceeded {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *limitedWriter) WriteHeaderNow() {
+	if !w.body.exceeded {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *limitedWriter) Write(data []byte) (int, error) {
+	if w.body.exceeded {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *limitedWriter) WriteString(s string) (int, error) {
+	if w.body.exceeded {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func abortPayloadTooLarge(c *gin.Context, maxBytes int64) {
+	err := core.ErrPayloadTooLarge(fmt.Errorf("request body exceeds %d bytes", maxBytes))
+	c.AbortWithStatusJSON(err.StatusCode(), err)
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Answer CORS preflight requests and add CORS headers to the others
+	Origins may be "*", an exact origin or a wildcard subdomain like https://*.example.com
+	Requests from other origins are served without CORS headers, preflights get 403
+	ginc registers it from the gin-cors-* flags
+*/
+
+type CORSConfig struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string // empty echoes the requested headers
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	allowAll := false
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+	}
+
+	methods := strings.Join(cfg.AllowMethods, ", ")
+	headers := strings.Join(cfg.AllowHeaders, ", ")
+	expose := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions &&
+			c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !allowAll && !matchOrigin(cfg.AllowOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		if allowAll && !cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if expose != "" {
+				h.Set("Access-Control-Expose-Headers", expose)
+			}
+			c.Next()
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", methods)
+		if headers != "" {
+			h.Set("Access-Control-Allow-Headers", headers)
+		} else if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+			h.Set("Access-Control-Allow-Headers", requested)
+		}
+		if cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func matchOrigin(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if strings.EqualFold(pattern, origin) {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(pattern, "*.")
+		if !ok {
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(strings.ToLower(origin), strings.ToLower(scheme))
+		if ok && strings.HasSuffix(rest, "."+strings.ToLower(host)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	sctx "github.com/DatLe328/service-context"
+	"github.com/DatLe328/service-context/core"
+	"github.com/DatLe328/service-context/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Stash the "http" logger in the request context and add request_id, method, path
+	and, when already authenticated, the requester subject to the context fields
+	Register it after the authentication middleware to get the subject
+	Log with the Ctx methods to get the fields, e.g. logger.FromContext(ctx).InfoCtx(ctx, "done")
+	Without this middleware FromContext discards entries unless given a fallback logger
+*/
+
+func ContextLogger(serviceCtx sctx.ServiceContext) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := logger.Fields{
+			KeyRequestID: ensureRequestID(c),
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+		}
+
+		if requester := requesterOf(c); requester != nil {
+			fields["requester"] = requester.GetSubject()
+		}
+
+		ctx := logger.ContextWithFields(c.Request.Context(), fields)
+		ctx = logger.WithContext(ctx, serviceCtx.Logger("http"))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func requesterOf(c *gin.Context) core.Requester {
+	if v, ok := c.Get(core.KeyRequester); ok {
+		if requester, ok := v.(core.Requester); ok {
+			return requester
+		}
+	}
+	return core.GetRequester(c.Request.Context())
+}
@@ -0,0 +1,384 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sctx "github.com/DatLe328/service-context"
+	"github.com/DatLe328/service-context/component/jwtc"
+	"github.com/DatLe328/service-context/core"
+	"github.com/DatLe328/service-context/logger"
+	"github.com/DatLe328/service-context/logger/loggertest"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handlers...)
+	return r
+}
+
+func serve(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestID(t *testing.T) {
+	r := newTestRouter(RequestID())
+	r.GET("/", func(c *gin.Context) {
+		fields := logger.FieldsFromContext(c.Request.Context())
+		c.String(http.StatusOK, "%s|%v", GetRequestID(c), fields[KeyRequestID])
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "abc-123")
+	w := serve(r, req)
+
+	if w.Header().Get(HeaderRequestID) != "abc-123" || w.Body.String() != "abc-123|abc-123" {
+		t.Fatalf("expected incoming id to be propagated, got %q %q", w.Header().Get(HeaderRequestID), w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "forged\nline")
+	w = serve(r, req)
+
+	if id := w.Header().Get(HeaderRequestID); len(id) != 32 {
+		t.Fatalf("expected a generated id, got %q", id)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	app := loggertest.New()
+	serviceCtx := sctx.NewServiceContext(sctx.WithAppLogger(app), sctx.WithArgs())
+
+	r := newTestRouter(RequestID(), AccessLog(serviceCtx, "/livez"))
+	r.GET("/livez", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Set(core.KeyRequester, core.NewRequester("u1", "t1"))
+		c.String(http.StatusNotFound, "missing")
+	})
+
+	serve(r, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1?full=true", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	serve(r, req)
+
+	app.AssertCount(t, "", "request", 1)
+
+	e := app.AssertLogged(t, loggertest.LevelWarn, "request")
+	e.AssertField(t, "status", http.StatusNotFound)
+	e.AssertField(t, "path", "/users/1")
+	e.AssertField(t, "query", "full=true")
+	e.AssertField(t, "bytes", len("missing"))
+	e.AssertField(t, KeyRequestID, "req-1")
+	e.AssertField(t, "requester", "u1")
+
+	app.Reset()
+	r.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	serve(r, httptest.NewRequest(http.MethodGet, "/empty", nil))
+
+	app.AssertLogged(t, loggertest.LevelInfo, "request").AssertField(t, "bytes", 0)
+}
+
+func TestContextLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	serviceCtx := sctx.NewServiceContext(sctx.WithArgs(
+		"-log-level", "info",
+		"-log-encoding", "json",
+		"-log-output-paths", path,
+	))
+	if err := serviceCtx.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestRouter(RequestID(), ContextLogger(serviceCtx))
+	r.GET("/users/:id", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logger.FromContext(ctx).InfoCtx(ctx, "handled")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	serve(r, req)
+
+	if err := serviceCtx.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var line string
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.Contains(l, `"msg":"handled"`) {
+			line = l
+		}
+	}
+	if line == "" {
+		t.Fatalf("handler entry not logged:\n%s", data)
+	}
+
+	for _, field := range []string{`"request_id":"req-1"`, `"method":"GET"`, `"path":"/users/1"`} {
+		if n := strings.Count(line, field); n != 1 {
+			t.Fatalf("expected %s once, got %d times in %s", field, n, line)
+		}
+	}
+}
+
+func TestCORS(t *testing.T) {
+	r := newTestRouter(CORS(CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		AllowMethods:     []string{"GET", "POST"},
+		ExposeHeaders:    []string{HeaderRequestID},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/", nil)
+	preflight.Header.Set("Origin", "https://api.example.org")
+	preflight.Header.Set("Access-Control-Request-Method", "POST")
+	preflight.Header.Set("Access-Control-Request-Headers", "Authorization")
+	w := serve(r, preflight)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") != "https://api.example.org" ||
+		h.Get("Access-Control-Allow-Methods") != "GET, POST" ||
+		h.Get("Access-Control-Allow-Headers") != "Authorization" ||
+		h.Get("Access-Control-Allow-Credentials") != "true" ||
+		h.Get("Access-Control-Max-Age") != "3600" {
+		t.Fatalf("unexpected preflight headers: %v", h)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w = serve(r, req)
+	if w.Header().Get("Access-Control-Expose-Headers") != HeaderRequestID {
+		t.Fatalf("unexpected headers: %v", w.Header())
+	}
+
+	preflight.Header.Set("Origin", "https://evil.com")
+	if w = serve(r, preflight); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for unknown origin, got %d", w.Code)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	r := newTestRouter(BodyLimit(8))
+	r.POST("/", func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	if w := serve(r, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("small"))); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	if w := serve(r, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("way too large"))); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 from content length, got %d", w.Code)
+	}
+
+	// unknown length, detected while reading
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("way too large")))
+	req.ContentLength = -1
+	w := serve(r, req)
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), "ErrPayloadTooLarge") {
+		t.Fatalf("expected 413 while reading, got %d %s", w.Code, w.Body.String())
+	}
+
+	// a binding handler answers 400, replaced by the 413
+	r.POST("/bind", func(c *gin.Context) {
+		var v map[string]string
+		if err := c.ShouldBindJSON(&v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req = httptest.NewRequest(http.MethodPost, "/bind", io.NopCloser(strings.NewReader(`{"name":"way too large"}`)))
+	req.ContentLength = -1
+	w = serve(r, req)
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), "ErrPayloadTooLarge") {
+		t.Fatalf("expected 413 from a binding handler, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func newTestJWT(t *testing.T) jwtc.TokenProvider {
+	t.Helper()
+
+	serviceCtx := sctx.NewServiceContext(
+		sctx.WithAppLogger(loggertest.New()),
+		sctx.WithArgs("-jwt-secret", "this-is-a-very-secure-secret-key-32bytes!"),
+		sctx.WithComponent(jwtc.NewJWT("jwt")),
+	)
+	if err := serviceCtx.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	t.Cleanup(func() { _ = serviceCtx.Stop() })
+
+	return serviceCtx.MustGet("jwt").(jwtc.TokenProvider)
+}
+
+func TestRequireAuth(t *testing.T) {
+	tp := newTestJWT(t)
+	token, _, err := tp.IssueToken(context.Background(), "tid-1", "user-1", 0)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	r := newTestRouter(RequireAuth(tp, WithTokenSources(HeaderToken("Authorization"), CookieToken("access_token"), QueryToken("token"))))
+	r.GET("/", func(c *gin.Context) {
+		requester := core.GetRequester(c.Request.Context())
+		fields := logger.FieldsFromContext(c.Request.Context())
+		c.String(http.StatusOK, "%s|%s|%v", requester.GetSubject(), requester.GetTokenId(), fields["requester"])
+	})
+
+	for name, req := range map[string]*http.Request{
+		"header": httptest.NewRequest(http.MethodGet, "/", nil),
+		"cookie": httptest.NewRequest(http.MethodGet, "/", nil),
+		"query":  httptest.NewRequest(http.MethodGet, "/?token="+token, nil),
+	} {
+		switch name {
+		case "header":
+			req.Header.Set("Authorization", "Bearer "+token)
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+		}
+
+		if w := serve(r, req); w.Code != http.StatusOK || w.Body.String() != "user-1|tid-1|user-1" {
+			t.Fatalf("%s: unexpected response %d %s", name, w.Code, w.Body.String())
+		}
+	}
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "ErrUnauthorized") {
+		t.Fatalf("expected ErrUnauthorized, got %d %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	w = serve(r, req)
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "ErrInvalidToken") {
+		t.Fatalf("expected ErrInvalidToken, got %d %s", w.Code, w.Body.String())
+	}
+
+	for _, value := range []string{"Bearer", "bearer   ", "Bearer \t", "Basic dXNlcjpwYXNz"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", value)
+		w := serve(r, req)
+		if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "ErrUnauthorized") {
+			t.Fatalf("%q: expected ErrUnauthorized, got %d %s", value, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestOptionalAuth(t *testing.T) {
+	tp := newTestJWT(t)
+
+	r := newTestRouter(OptionalAuth(tp))
+	r.GET("/", func(c *gin.Context) {
+		if core.GetRequester(c.Request.Context()) != nil {
+			c.String(http.StatusOK, "authenticated")
+			return
+		}
+		c.String(http.StatusOK, "anonymous")
+	})
+
+	if w := serve(r, httptest.NewRequest(http.MethodGet, "/", nil)); w.Body.String() != "anonymous" {
+		t.Fatalf("unexpected response %d %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	if w := serve(r, req); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad token, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	if w := serve(r, req); w.Code != http.StatusOK || w.Body.String() != "anonymous" {
+		t.Fatalf("basic credentials are not a token, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRequireRolesAndScopes(t *testing.T) {
+	tp := newTestJWT(t)
+
+	issue := func(roles, scopes []string) string {
+		claims := &jwtc.Claims{Roles: roles, Scopes: scopes}
+		claims.Subject = "user-1"
+
+		token, _, err := tp.(jwtc.ClaimsProvider).IssueClaims(context.Background(), claims, 0)
+		if err != nil {
+			t.Fatalf("issue token: %v", err)
+		}
+		return token
+	}
+
+	r := newTestRouter()
+	r.GET("/admin", RequireAuth(tp), RequireRoles("admin", "owner"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/orders", RequireAuth(tp), RequireScopes("orders:read", "orders:write"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/public", RequireRoles("admin"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := []struct {
+		path  string
+		token string
+		code  int
+	}{
+		{"/admin", issue([]string{"owner"}, nil), http.StatusOK},
+		{"/admin", issue([]string{"user"}, nil), http.StatusForbidden},
+		{"/orders", issue(nil, []string{"orders:read", "orders:write"}), http.StatusOK},
+		{"/orders", issue(nil, []string{"orders:read"}), http.StatusForbidden},
+		{"/public", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+
+		w := serve(r, req)
+		if w.Code != tc.code {
+			t.Fatalf("%s: expected %d, got %d %s", tc.path, tc.code, w.Code, w.Body.String())
+		}
+		if tc.code == http.StatusForbidden && !strings.Contains(w.Body.String(), "ErrNoPermission") {
+			t.Fatalf("%s: expected ErrNoPermission, got %s", tc.path, w.Body.String())
+		}
+	}
+}
+
+func TestRequireRolesAndScopes_PanicWhenEmpty(t *testing.T) {
+	for name, build := range map[string]func() gin.HandlerFunc{
+		"roles":  func() gin.HandlerFunc { return RequireRoles() },
+		"scopes": func() gin.HandlerFunc { return RequireScopes() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected a panic without arguments", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
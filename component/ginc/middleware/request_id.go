@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"github.com/DatLe328/service-context/core"
+	"github.com/DatLe328/service-context/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+	KeyRequestID    = "request_id"
+
+	maxRequestIDLength = 128
+)
+
+/*
+	Propagate X-Request-ID: reuse the incoming one when valid, generate one otherwise
+	The id is echoed in the response, stored in gin.Context under KeyRequestID
+	and added to the request context fields of the logger
+*/
+
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := ensureRequestID(c)
+
+		ctx := logger.ContextWithFields(c.Request.Context(), logger.Fields{KeyRequestID: requestID})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// GetRequestID returns the id set by RequestID or ContextLogger.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(KeyRequestID)
+}
+
+func ensureRequestID(c *gin.Context) string {
+	if requestID := GetRequestID(c); requestID != "" {
+		return requestID
+	}
+
+	requestID := c.GetHeader(HeaderRequestID)
+	if !validRequestID(requestID) {
+		requestID, _ = core.GenHex(32)
+		c.Request.Header.Set(HeaderRequestID, requestID)
+	}
+
+	c.Header(HeaderRequestID, requestID)
+	c.Set(KeyRequestID, requestID)
+	return requestID
+}
+
+// validRequestID rejects ids that could be used to forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -7,5 +7,6 @@ import (
 
 // user:password@tcp(localhost:3306)/dbname?charset=utf8mb4&parseTime=True&loc=Local
 func MySQLDB(dsn string) (db *gorm.DB, error error) {
-	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	// connectivity is checked by the component with a bounded context
+	return gorm.Open(mysql.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
 }
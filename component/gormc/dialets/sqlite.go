@@ -7,5 +7,6 @@ import (
 
 // /tmp/gorm.db
 func SQLiteDB(dsn string) (db *gorm.DB, err error) {
-	return gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	// connectivity is checked by the component with a bounded context
+	return gorm.Open(sqlite.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
 }
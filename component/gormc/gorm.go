@@ -1,9 +1,14 @@
 package gormc
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	sctx "github.com/DatLe328/service-context"
@@ -29,12 +34,19 @@ type GormOpt struct {
 	maxConnectionIdleTime int
 }
 
+type GormComponent interface {
+	GetDB() *gorm.DB
+}
+
+var _ GormComponent = (*gormDB)(nil)
+
 type gormDB struct {
 	id       string
 	prefix   string
 	logger   logger.Logger
 	logLevel string
 	db       *gorm.DB
+	mu       sync.RWMutex
 	*GormOpt
 }
 
@@ -50,42 +62,52 @@ func (gdb *gormDB) ID() string {
 	return gdb.id
 }
 
-func (gdb *gormDB) InitFlags() {
+func (gdb *gormDB) SensitiveFlags() []string {
 	prefix := gdb.prefix
 
 	if prefix != "" {
 		prefix += "-"
 	}
 
-	flag.StringVar(
+	return []string{fmt.Sprintf("%sdb-dsn", prefix)}
+}
+
+func (gdb *gormDB) InitFlags(fs *flag.FlagSet) {
+	prefix := gdb.prefix
+
+	if prefix != "" {
+		prefix += "-"
+	}
+
+	fs.StringVar(
 		&gdb.dsn,
 		fmt.Sprintf("%sdb-dsn", prefix),
 		"",
 		"Database dsn",
 	)
 
-	flag.StringVar(
+	fs.StringVar(
 		&gdb.dbType,
 		fmt.Sprintf("%sdb-driver", prefix),
 		"mysql",
 		"Database driver (mysql, postgres) - Default mysql",
 	)
 
-	flag.IntVar(
+	fs.IntVar(
 		&gdb.maxOpenConnections,
 		fmt.Sprintf("%sdb-max-conn", prefix),
 		30,
 		"maximum number of open connections to the database - Default 30",
 	)
 
-	flag.IntVar(
+	fs.IntVar(
 		&gdb.maxIdleConnections,
 		fmt.Sprintf("%sdb-max-idle-conn", prefix),
 		10,
 		"maximum number of database connections in the idle - Default 10",
 	)
 
-	flag.IntVar(
+	fs.IntVar(
 		&gdb.maxConnectionIdleTime,
 		fmt.Sprintf("%sdb-max-conn-idle-time", prefix),
 		3600,
@@ -94,21 +116,29 @@ func (gdb *gormDB) InitFlags() {
 }
 
 func (gdb *gormDB) Activate(serviceCtx sctx.ServiceContext) error {
+	return gdb.ActivateContext(context.Background(), serviceCtx)
+}
+
+func (gdb *gormDB) Validate() error {
+	if getDBType(gdb.dbType) == GormDBTypeNotSupported {
+		return fmt.Errorf("Database type not supported: %s", gdb.dbType)
+	}
+	return nil
+}
+
+func (gdb *gormDB) ActivateContext(ctx context.Context, serviceCtx sctx.ServiceContext) error {
 	gdb.logger = serviceCtx.Logger(gdb.id)
 	gdb.logLevel = serviceCtx.LogLevel()
 
 	gdb.logger.Infof(
-		"gorm initialized (db=%s, log_level=%s)",
+		"gorm initialized (db=%s, dsn=%s, log_level=%s)",
 		gdb.dbType,
+		sctx.RedactDSN(gdb.dsn),
 		gdb.logLevel,
 	)
 
 	dbType := getDBType(gdb.dbType)
 
-	if dbType == GormDBTypeNotSupported {
-		return errors.New("Database type not supported")
-	}
-
 	gdb.logger.Info("Connecting to database...")
 
 	conn, err := gdb.getDBConn(dbType)
@@ -118,6 +148,17 @@ func (gdb *gormDB) Activate(serviceCtx sctx.ServiceContext) error {
 		return err
 	}
 
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return err
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		gdb.logger.Error("cannot ping database", err.Error())
+		_ = sqlDB.Close()
+		return err
+	}
+
 	gdb.db = conn
 
 	return nil
@@ -138,6 +179,19 @@ func (gdb *gormDB) Stop() error {
 	return sqlDB.Close()
 }
 
+func (gdb *gormDB) HealthCheck(ctx context.Context) error {
+	if gdb.db == nil {
+		return errors.New("database is not connected")
+	}
+
+	sqlDB, err := gdb.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
 func (gdb *gormDB) GetDB() *gorm.DB {
 	if gdb.logLevel == "debug" {
 		return gdb.db.Session(&gorm.Session{NewDB: true}).Debug()
@@ -149,16 +203,68 @@ func (gdb *gormDB) GetDB() *gorm.DB {
 	})
 
 	if sqlDB, err := newSessionDB.DB(); err == nil {
-		sqlDB.SetMaxOpenConns(gdb.maxOpenConnections)
-		sqlDB.SetMaxIdleConns(gdb.maxIdleConnections)
-		sqlDB.SetConnMaxIdleTime(
-			time.Second * time.Duration(gdb.maxConnectionIdleTime),
-		)
+		gdb.applyPoolSettings(sqlDB)
 	}
 
 	return newSessionDB
 }
 
+func (gdb *gormDB) applyPoolSettings(sqlDB *sql.DB) {
+	gdb.mu.RLock()
+	defer gdb.mu.RUnlock()
+
+	sqlDB.SetMaxOpenConns(gdb.maxOpenConnections)
+	sqlDB.SetMaxIdleConns(gdb.maxIdleConnections)
+	sqlDB.SetConnMaxIdleTime(
+		time.Second * time.Duration(gdb.maxConnectionIdleTime),
+	)
+}
+
+// Reload applies new connection pool sizes. The dsn and driver require a
+// restart.
+func (gdb *gormDB) Reload(changed map[string]string) error {
+	prefix := gdb.prefix
+
+	if prefix != "" {
+		prefix += "-"
+	}
+
+	var errs []error
+
+	gdb.mu.Lock()
+	for key, value := range changed {
+		var target *int
+
+		switch strings.TrimPrefix(key, prefix) {
+		case "db-max-conn":
+			target = &gdb.maxOpenConnections
+		case "db-max-idle-conn":
+			target = &gdb.maxIdleConnections
+		case "db-max-conn-idle-time":
+			target = &gdb.maxConnectionIdleTime
+		default:
+			errs = append(errs, fmt.Errorf("%s: %w", key, sctx.ErrRestartRequired))
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", key, err))
+			continue
+		}
+		*target = n
+	}
+	gdb.mu.Unlock()
+
+	if gdb.db != nil {
+		if sqlDB, err := gdb.db.DB(); err == nil {
+			gdb.applyPoolSettings(sqlDB)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func getDBType(dbType string) GormDBType {
 	switch dbType {
 	case "mysql":
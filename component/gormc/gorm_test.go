@@ -1,6 +1,7 @@
 package gormc
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -43,10 +44,13 @@ type TestUser struct {
 }
 
 func TestGormDB_InsertFind_Success(t *testing.T) {
-	gormComp := testServiceCtx.MustGet("gorm").(*gormDB)
+	gormComp, err := sctx.GetAs[GormComponent](testServiceCtx, "gorm")
+	if err != nil {
+		t.Fatal(err)
+	}
 	db := gormComp.GetDB()
 
-	err := db.AutoMigrate(&TestUser{})
+	err = db.AutoMigrate(&TestUser{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -62,3 +66,15 @@ func TestGormDB_InsertFind_Success(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestGormDB_Health_Success(t *testing.T) {
+	report := testServiceCtx.Health(context.Background())
+
+	if !report.Healthy() {
+		t.Fatalf("expected healthy report, got %+v", report)
+	}
+
+	if report.Components["gorm"].Status != sctx.HealthStatusUp {
+		t.Fatalf("gorm should be up: %+v", report.Components["gorm"])
+	}
+}
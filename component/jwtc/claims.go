@@ -0,0 +1,121 @@
+package jwtc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoRegisteredClaims = errors.New("claims must embed jwt.RegisteredClaims")
+
+// Claims are the registered claims plus the roles and scopes granted to the
+// subject.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// ClaimsProvider is implemented by token providers able to issue and parse
+// roles and scopes.
+type ClaimsProvider interface {
+	IssueClaims(ctx context.Context, claims *Claims, seconds int) (token string, expSecs int, err error)
+	ParseClaims(ctx context.Context, tokenString string) (*Claims, error)
+}
+
+var _ ClaimsProvider = (*jwtx)(nil)
+
+// ClaimsSigner is implemented by token providers able to sign and verify any
+// claims, which IssueWithClaims and ParseWithClaims build on. claims must embed
+// jwt.RegisteredClaims, completed by the signer.
+type ClaimsSigner interface {
+	SignClaims(ctx context.Context, claims jwt.Claims, seconds int) (token string, expSecs int, err error)
+	VerifyClaims(ctx context.Context, tokenString string, claims jwt.Claims) error
+}
+
+var _ ClaimsSigner = (*jwtx)(nil)
+
+func (j *jwtx) IssueClaims(ctx context.Context, claims *Claims, seconds int) (token string, expSecs int, err error) {
+	return IssueWithClaims(ctx, j, claims, seconds)
+}
+
+func (j *jwtx) ParseClaims(ctx context.Context, tokenString string) (*Claims, error) {
+	return ParseWithClaims[Claims](ctx, j, tokenString)
+}
+
+/*
+	Custom claims are user-defined structs embedding jwt.RegisteredClaims, e.g.
+
+	type SessionClaims struct {
+		jwt.RegisteredClaims
+		TenantID string `json:"tenant_id"`
+	}
+
+	signer := sc.MustGet("jwt").(jwtc.ClaimsSigner)
+	token, exp, err := jwtc.IssueWithClaims(ctx, signer, &SessionClaims{TenantID: "t1"}, 0)
+	claims, err := jwtc.ParseWithClaims[SessionClaims](ctx, signer, token)
+*/
+
+// IssueWithClaims signs claims with s. The expiry, issue time, issuer and
+// audience of the embedded registered claims are set like IssueToken does.
+func IssueWithClaims[T any, PT interface {
+	*T
+	jwt.Claims
+}](ctx context.Context, s ClaimsSigner, claims PT, seconds int) (token string, expSecs int, err error) {
+	return s.SignClaims(ctx, claims, seconds)
+}
+
+// ParseWithClaims verifies tokenString with s and decodes it into a T.
+func ParseWithClaims[T any, PT interface {
+	*T
+	jwt.Claims
+}](ctx context.Context, s ClaimsSigner, tokenString string) (*T, error) {
+	claims := PT(new(T))
+	if _, err := registeredClaimsOf(claims); err != nil {
+		return nil, err
+	}
+
+	if err := s.VerifyClaims(ctx, tokenString, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+var registeredClaimsType = reflect.TypeOf(jwt.RegisteredClaims{})
+
+// registeredClaimsOf returns the jwt.RegisteredClaims embedded in the struct
+// claims points to, allocating it when embedded by pointer.
+func registeredClaimsOf(claims any) (*jwt.RegisteredClaims, error) {
+	if rc, ok := claims.(*jwt.RegisteredClaims); ok {
+		return rc, nil
+	}
+
+	v := reflect.ValueOf(claims)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w, got %T", ErrNoRegisteredClaims, claims)
+	}
+
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.Anonymous {
+			continue
+		}
+
+		switch field.Type {
+		case registeredClaimsType:
+			return v.Field(i).Addr().Interface().(*jwt.RegisteredClaims), nil
+		case reflect.PointerTo(registeredClaimsType):
+			if v.Field(i).IsNil() {
+				v.Field(i).Set(reflect.New(registeredClaimsType))
+			}
+			return v.Field(i).Interface().(*jwt.RegisteredClaims), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w, got %T", ErrNoRegisteredClaims, claims)
+}
@@ -0,0 +1,132 @@
+package jwtc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySetProvider is implemented by token providers publishing the public keys
+// that verify their tokens.
+type KeySetProvider interface {
+	JWKS() JWKSet
+}
+
+var _ KeySetProvider = (*jwtx)(nil)
+
+// JWKS returns the public verification keys. It is empty with HS256, whose
+// secret must never be published.
+func (j *jwtx) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	for _, vk := range j.verifyKeys {
+		jwk, err := newJWK(vk.key)
+		if err != nil {
+			continue
+		}
+
+		jwk.Kid = vk.kid
+		jwk.Use = "sig"
+		jwk.Alg = j.signingMethod().Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// JWKSHandler serves JWKS, typically on GET /.well-known/jwks.json.
+func JWKSHandler(p KeySetProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, p.JWKS())
+	}
+}
+
+func newJWK(key crypto.PublicKey) (JWK, error) {
+	enc := base64.RawURLEncoding
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   enc.EncodeToString(k.N.Bytes()),
+			E:   enc.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		pub, err := k.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+
+		// uncompressed point: 0x04 || X || Y
+		point := pub.Bytes()[1:]
+		size := len(point) / 2
+
+		return JWK{
+			Kty: "EC",
+			Crv: k.Curve.Params().Name,
+			X:   enc.EncodeToString(point[:size]),
+			Y:   enc.EncodeToString(point[size:]),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   enc.EncodeToString(k),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// thumbprint computes the RFC 7638 thumbprint of key, used as default kid.
+func thumbprint(key crypto.PublicKey) (string, error) {
+	jwk, err := newJWK(key)
+	if err != nil {
+		return "", err
+	}
+
+	// json sorts map keys, as the RFC requires
+	members := map[string]string{"kty": jwk.Kty}
+	switch jwk.Kty {
+	case "RSA":
+		members["e"], members["n"] = jwk.E, jwk.N
+	case "EC":
+		members["crv"], members["x"], members["y"] = jwk.Crv, jwk.X, jwk.Y
+	case "OKP":
+		members["crv"], members["x"] = jwk.Crv, jwk.X
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
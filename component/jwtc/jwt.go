@@ -2,12 +2,16 @@ package jwtc
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"flag"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	sctx "github.com/DatLe328/service-context"
+	"github.com/DatLe328/service-context/internal/flagutil"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -19,12 +23,32 @@ const (
 var (
 	ErrSecretKeyNotValid     = errors.New("secret key must be in 32 bytes")
 	ErrTokenLifeTimeTooShort = errors.New("token life time too short")
+	ErrVerifyKeysMissing     = errors.New("jwt private key file or public key files are required with asymmetric algorithms")
 )
 
+type TokenProvider interface {
+	IssueToken(ctx context.Context, id, sub string, seconds int) (token string, expSecs int, err error)
+	ParseToken(ctx context.Context, tokenString string) (*jwt.RegisteredClaims, error)
+}
+
+var _ TokenProvider = (*jwtx)(nil)
+
 type jwtx struct {
 	id                   string
 	secret               string
+	issuer               string
+	audience             string
+	alg                  string
+	privateKeyFile       string
+	publicKeyFiles       string
+	keyID                string
+	mu                   sync.RWMutex
 	expireTokenInSeconds int
+
+	method     jwt.SigningMethod
+	signKey    crypto.Signer
+	kid        string
+	verifyKeys []verifyKey
 }
 
 func NewJWT(id string) *jwtx {
@@ -37,52 +61,233 @@ func (j *jwtx) ID() string {
 	return j.id
 }
 
-func (j *jwtx) InitFlags() {
-	flag.StringVar(
+func (j *jwtx) SensitiveFlags() []string {
+	return []string{"jwt-secret"}
+}
+
+func (j *jwtx) InitFlags(fs *flag.FlagSet) {
+	fs.StringVar(
 		&j.secret,
 		"jwt-secret",
 		defaultSecret,
 		"Secret key to sign JWT")
-	flag.IntVar(
+	fs.IntVar(
 		&j.expireTokenInSeconds,
 		"jwt-exp-secs",
 		defaultExpireTokenInSeconds,
 		"Token life time in second")
+	fs.StringVar(
+		&j.issuer,
+		"jwt-issuer",
+		"",
+		"Issuer (iss) set on issued tokens and required on parsed ones")
+	fs.StringVar(
+		&j.audience,
+		"jwt-audience",
+		"",
+		"Comma-separated audiences (aud) set on issued tokens, parsed ones must have one of them")
+	fs.StringVar(
+		&j.alg,
+		"jwt-algorithm",
+		AlgHS256,
+		"Signing algorithm: HS256 | RS256 | ES256 | EdDSA")
+	fs.StringVar(
+		&j.privateKeyFile,
+		"jwt-private-key-file",
+		"",
+		"PEM private key signing tokens with RS256, ES256 or EdDSA")
+	fs.StringVar(
+		&j.publicKeyFiles,
+		"jwt-public-key-files",
+		"",
+		"Comma-separated PEM public keys also accepted to verify tokens, e.g. previous keys or keys of a verify-only service")
+	fs.StringVar(
+		&j.keyID,
+		"jwt-key-id",
+		"",
+		"kid header of issued tokens. Default the RFC 7638 thumbprint of the public key")
 }
 
-func (j *jwtx) Activate(_ sctx.ServiceContext) error {
-	if len(j.secret) < 32 {
-		return ErrSecretKeyNotValid
+func (j *jwtx) Validate() error {
+	var errs []error
+
+	method, err := signingMethod(j.alg)
+	switch {
+	case err != nil:
+		errs = append(errs, err)
+	case method == jwt.SigningMethodHS256:
+		if len(j.secret) < 32 {
+			errs = append(errs, ErrSecretKeyNotValid)
+		}
+	default:
+		if j.privateKeyFile == "" && len(flagutil.SplitList(j.publicKeyFiles)) == 0 {
+			errs = append(errs, ErrVerifyKeysMissing)
+		}
 	}
+
 	if j.expireTokenInSeconds < 60 {
-		return ErrTokenLifeTimeTooShort
+		errs = append(errs, ErrTokenLifeTimeTooShort)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (j *jwtx) Activate(_ sctx.ServiceContext) error {
+	return j.loadKeys()
+}
+
+// loadKeys reads the PEM keys of asymmetric algorithms. Without private key
+// the component only verifies tokens.
+func (j *jwtx) loadKeys() error {
+	method, err := signingMethod(j.alg)
+	if err != nil {
+		return err
 	}
+	j.method = method
+	j.kid = j.keyID
+	j.signKey = nil
+	j.verifyKeys = nil
+
+	if method == jwt.SigningMethodHS256 {
+		return nil
+	}
+
+	var keys []verifyKey
+
+	if j.privateKeyFile != "" {
+		signer, err := loadPrivateKey(j.privateKeyFile, j.alg)
+		if err != nil {
+			return err
+		}
+
+		if j.kid == "" {
+			if j.kid, err = thumbprint(signer.Public()); err != nil {
+				return err
+			}
+		}
+
+		j.signKey = signer
+		keys = append(keys, verifyKey{kid: j.kid, key: signer.Public()})
+	}
+
+	for _, path := range flagutil.SplitList(j.publicKeyFiles) {
+		key, err := loadPublicKey(path, j.alg)
+		if err != nil {
+			return err
+		}
+
+		// the signing key may be listed too, published under its own kid
+		if hasVerifyKey(keys, key) {
+			continue
+		}
+
+		kid, err := thumbprint(key)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, verifyKey{kid: kid, key: key})
+	}
+
+	if len(keys) == 0 {
+		return ErrVerifyKeysMissing
+	}
+
+	j.verifyKeys = keys
 	return nil
 }
 
+// Reload applies a new token life time. Changing the secret would invalidate
+// every issued token, so it requires a restart.
+func (j *jwtx) Reload(changed map[string]string) error {
+	var errs []error
+
+	for key, value := range changed {
+		if key != "jwt-exp-secs" {
+			errs = append(errs, fmt.Errorf("%s: %w", key, sctx.ErrRestartRequired))
+			continue
+		}
+
+		secs, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", key, err))
+			continue
+		}
+
+		if secs < 60 {
+			errs = append(errs, ErrTokenLifeTimeTooShort)
+			continue
+		}
+
+		j.mu.Lock()
+		j.expireTokenInSeconds = secs
+		j.mu.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
 func (j *jwtx) Stop() error {
 	return nil
 }
 
 func (j *jwtx) IssueToken(ctx context.Context, id, sub string, seconds int) (token string, expSecs int, err error) {
+	claims := &jwt.RegisteredClaims{
+		Subject: sub,
+		ID:      id,
+	}
+
+	return j.SignClaims(ctx, claims, seconds)
+}
+
+func (j *jwtx) ParseToken(ctx context.Context, tokenString string) (*jwt.RegisteredClaims, error) {
+	var claims jwt.RegisteredClaims
+	if err := j.VerifyClaims(ctx, tokenString, &claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+// SignClaims sets the time, issuer and audience of the registered claims
+// embedded in claims, then signs claims.
+func (j *jwtx) SignClaims(ctx context.Context, claims jwt.Claims, seconds int) (token string, expSecs int, err error) {
+	rc, err := registeredClaimsOf(claims)
+	if err != nil {
+		return "", 0, err
+	}
+
 	now := time.Now().UTC()
 
+	j.mu.RLock()
 	exp := j.expireTokenInSeconds
+	j.mu.RUnlock()
+
 	if seconds > 0 {
 		exp = seconds
 	}
 
-	claims := jwt.RegisteredClaims{
-		Subject:   sub,
-		ExpiresAt: jwt.NewNumericDate(now.Add(time.Second * time.Duration(exp))),
-		NotBefore: jwt.NewNumericDate(now),
-		IssuedAt:  jwt.NewNumericDate(now),
-		ID:        id,
+	rc.ExpiresAt = jwt.NewNumericDate(now.Add(time.Second * time.Duration(exp)))
+	rc.NotBefore = jwt.NewNumericDate(now)
+	rc.IssuedAt = jwt.NewNumericDate(now)
+
+	if j.issuer != "" {
+		rc.Issuer = j.issuer
+	}
+	if audience := j.audiences(); len(audience) > 0 {
+		rc.Audience = audience
+	}
+
+	key, err := j.signingKey()
+	if err != nil {
+		return "", 0, err
 	}
 
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	t := jwt.NewWithClaims(j.signingMethod(), claims)
+	if j.kid != "" {
+		t.Header["kid"] = j.kid
+	}
 
-	tokenSignedStr, err := t.SignedString([]byte(j.secret))
+	tokenSignedStr, err := t.SignedString(key)
 
 	if err != nil {
 		return "", 0, err
@@ -91,27 +296,88 @@ func (j *jwtx) IssueToken(ctx context.Context, id, sub string, seconds int) (tok
 	return tokenSignedStr, exp, nil
 }
 
-func (j *jwtx) ParseToken(ctx context.Context, tokenString string) (*jwt.RegisteredClaims, error) {
+// VerifyClaims verifies tokenString, including the issuer and audience when
+// set, and decodes it into claims. Tokens without expiry are rejected.
+func (j *jwtx) VerifyClaims(ctx context.Context, tokenString string, claims jwt.Claims) error {
 	if j == nil {
-		return nil, errors.New("jwt component is nil")
+		return errors.New("jwt component is nil")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if j.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.issuer))
+	}
+	if audience := j.audiences(); len(audience) > 0 {
+		opts = append(opts, jwt.WithAudience(audience...))
 	}
 
-	var rc jwt.RegisteredClaims
-	token, err := jwt.ParseWithClaims(tokenString, &rc, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+	method := j.signingMethod()
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// only accept the configured algorithm, e.g. no HS256 token signed
+		// with a published RSA key
+		if token.Method.Alg() != method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return []byte(j.secret), nil
-	})
+		if method == jwt.SigningMethodHS256 {
+			return []byte(j.secret), nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return j.verificationKey(kid)
+	}, opts...)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if token == nil || !token.Valid {
-		return nil, errors.New("invalid token")
+		return errors.New("invalid token")
+	}
+
+	return nil
+}
+
+func (j *jwtx) signingMethod() jwt.SigningMethod {
+	if j.method == nil {
+		return jwt.SigningMethodHS256
+	}
+	return j.method
+}
+
+func (j *jwtx) signingKey() (interface{}, error) {
+	if j.signingMethod() == jwt.SigningMethodHS256 {
+		return []byte(j.secret), nil
 	}
+	if j.signKey == nil {
+		return nil, ErrSigningKeyMissing
+	}
+	return j.signKey, nil
+}
+
+// verificationKey picks the key of kid, or the only key for tokens without
+// kid.
+func (j *jwtx) verificationKey(kid string) (crypto.PublicKey, error) {
+	if kid == "" && len(j.verifyKeys) == 1 {
+		return j.verifyKeys[0].key, nil
+	}
+
+	if vk := j.findVerifyKey(kid); vk != nil {
+		return vk.key, nil
+	}
+	return nil, ErrVerifyKeyNotFound
+}
+
+func (j *jwtx) findVerifyKey(kid string) *verifyKey {
+	for i := range j.verifyKeys {
+		if j.verifyKeys[i].kid == kid {
+			return &j.verifyKeys[i]
+		}
+	}
+	return nil
+}
 
-	return &rc, nil
+func (j *jwtx) audiences() []string {
+	return flagutil.SplitList(j.audience)
 }
@@ -2,10 +2,12 @@ package jwtc
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
 	sctx "github.com/DatLe328/service-context"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 var testServiceCtx sctx.ServiceContext
@@ -53,7 +55,7 @@ func TestJWT_IssueToken_Success(t *testing.T) {
 }
 
 func TestJWT_ParseToken_Success(t *testing.T) {
-	j := testServiceCtx.MustGet("jwt").(*jwtx)
+	j := sctx.MustGetAs[TokenProvider](testServiceCtx, "jwt")
 
 	token, _, err := j.IssueToken(
 		context.Background(),
@@ -99,3 +101,141 @@ func TestJWT_ParseToken_InvalidToken(t *testing.T) {
 		t.Fatal("expected error for invalid token")
 	}
 }
+
+func TestJWT_Validate_ReportsAllErrors(t *testing.T) {
+	j := &jwtx{secret: "short", expireTokenInSeconds: 10}
+
+	err := j.Validate()
+	if !errors.Is(err, ErrSecretKeyNotValid) || !errors.Is(err, ErrTokenLifeTimeTooShort) {
+		t.Fatalf("expected both validation errors, got %v", err)
+	}
+}
+
+func TestJWT_Reload_ExpireTime(t *testing.T) {
+	j := &jwtx{secret: "this-is-a-very-secure-secret-key-32bytes!", expireTokenInSeconds: 60}
+
+	if err := j.Reload(map[string]string{"jwt-exp-secs": "120"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, exp, err := j.IssueToken(context.Background(), "id", "user", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp != 120 {
+		t.Fatalf("expected exp=120 after reload, got %d", exp)
+	}
+
+	if err := j.Reload(map[string]string{"jwt-secret": "another-very-secure-secret-key-32bytes!"}); !errors.Is(err, sctx.ErrRestartRequired) {
+		t.Fatalf("secret should require a restart, got %v", err)
+	}
+}
+
+func TestJWT_IssueClaims_RolesAndScopes(t *testing.T) {
+	j := testServiceCtx.MustGet("jwt").(*jwtx)
+
+	claims := &Claims{Roles: []string{"admin"}, Scopes: []string{"orders:read", "orders:write"}}
+	claims.Subject = "user-1"
+
+	token, _, err := j.IssueClaims(context.Background(), claims, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := j.ParseClaims(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Subject != "user-1" || len(parsed.Roles) != 1 || parsed.Roles[0] != "admin" || len(parsed.Scopes) != 2 {
+		t.Fatalf("unexpected claims: %+v", parsed)
+	}
+}
+
+type sessionClaims struct {
+	jwt.RegisteredClaims
+	TenantID  string `json:"tenant_id"`
+	SessionID string `json:"sid"`
+}
+
+func TestJWT_CustomClaims(t *testing.T) {
+	signer := testServiceCtx.MustGet("jwt").(ClaimsSigner)
+
+	claims := &sessionClaims{TenantID: "tenant-1", SessionID: "s-1"}
+	claims.Subject = "user-1"
+
+	token, exp, err := IssueWithClaims(context.Background(), signer, claims, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != 60 || claims.ExpiresAt == nil {
+		t.Fatalf("expected expiry to be set, got %d %v", exp, claims.ExpiresAt)
+	}
+
+	parsed, err := ParseWithClaims[sessionClaims](context.Background(), signer, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Subject != "user-1" || parsed.TenantID != "tenant-1" || parsed.SessionID != "s-1" {
+		t.Fatalf("unexpected claims: %+v", parsed)
+	}
+
+	type noRegistered struct {
+		jwt.MapClaims
+	}
+	if _, _, err := IssueWithClaims(context.Background(), signer, &noRegistered{}, 0); !errors.Is(err, ErrNoRegisteredClaims) {
+		t.Fatalf("expected ErrNoRegisteredClaims, got %v", err)
+	}
+}
+
+func TestJWT_RejectsTokenWithoutExpiry(t *testing.T) {
+	j := testServiceCtx.MustGet("jwt").(*jwtx)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{Subject: "user-1"}).
+		SignedString([]byte(j.secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.ParseToken(context.Background(), token); !errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+		t.Fatalf("expected a missing exp error, got %v", err)
+	}
+}
+
+func TestJWT_IssuerAndAudience(t *testing.T) {
+	issuer := &jwtx{
+		secret:               "this-is-a-very-secure-secret-key-32bytes!",
+		expireTokenInSeconds: 60,
+		issuer:               "auth-service",
+		audience:             "orders, billing",
+	}
+
+	token, _, err := issuer.IssueToken(context.Background(), "tid", "user-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := issuer.ParseToken(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Issuer != "auth-service" || len(claims.Audience) != 2 {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	billing := &jwtx{secret: issuer.secret, issuer: "auth-service", audience: "billing"}
+	if _, err := billing.ParseToken(context.Background(), token); err != nil {
+		t.Fatalf("expected billing audience to be accepted: %v", err)
+	}
+
+	other := &jwtx{secret: issuer.secret, issuer: "auth-service", audience: "reports"}
+	if _, err := other.ParseToken(context.Background(), token); !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Fatalf("expected invalid audience, got %v", err)
+	}
+
+	forged := &jwtx{secret: issuer.secret, issuer: "other-service"}
+	if _, err := forged.ParseToken(context.Background(), token); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Fatalf("expected invalid issuer, got %v", err)
+	}
+}
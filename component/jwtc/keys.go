@@ -0,0 +1,151 @@
+package jwtc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported jwt algorithm")
+	ErrSigningKeyMissing    = errors.New("jwt private key is required to issue tokens")
+	ErrVerifyKeyNotFound    = errors.New("no jwt verification key matches the token")
+)
+
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case "", AlgHS256:
+		return jwt.SigningMethodHS256, nil
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgES256:
+		return jwt.SigningMethodES256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("%w: %s (allowed: HS256 | RS256 | ES256 | EdDSA)", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// verifyKey is a public key accepted to verify tokens, published in the JWKS.
+type verifyKey struct {
+	kid string
+	key crypto.PublicKey
+}
+
+func hasVerifyKey(keys []verifyKey, key crypto.PublicKey) bool {
+	for _, vk := range keys {
+		if k, ok := vk.key.(interface{ Equal(crypto.PublicKey) bool }); ok && k.Equal(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadPrivateKey(path, alg string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse private key %s: %w", path, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("private key %s: unsupported key type %T", path, key)
+	}
+
+	if err := checkKeyType(signer.Public(), alg); err != nil {
+		return nil, fmt.Errorf("private key %s: %w", path, err)
+	}
+	return signer, nil
+}
+
+func loadPublicKey(path, alg string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key any
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse public key %s: %w", path, err)
+	}
+
+	if err := checkKeyType(key, alg); err != nil {
+		return nil, fmt.Errorf("public key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	return block, nil
+}
+
+func checkKeyType(key crypto.PublicKey, alg string) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg == AlgRS256 {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if alg == AlgES256 {
+			if k.Curve != elliptic.P256() {
+				return errors.New("ES256 requires a P-256 key")
+			}
+			return nil
+		}
+	case ed25519.PublicKey:
+		if alg == AlgEdDSA {
+			return nil
+		}
+	}
+	return fmt.Errorf("key type %T cannot be used with %s", key, alg)
+}
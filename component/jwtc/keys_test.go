@@ -0,0 +1,211 @@
+package jwtc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeKeyPair(t *testing.T, key crypto.Signer) (privatePath, publicPath string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath = filepath.Join(dir, "private.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	der, err = x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPath = filepath.Join(dir, "public.pem")
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return privatePath, publicPath
+}
+
+func newActivatedJWT(t *testing.T, args ...string) *jwtx {
+	t.Helper()
+
+	j := NewJWT("jwt")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	j.InitFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := j.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := j.Activate(nil); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	return j
+}
+
+func TestJWT_AsymmetricAlgorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	for alg, key := range map[string]crypto.Signer{AlgRS256: rsaKey, AlgES256: ecKey, AlgEdDSA: edKey} {
+		privatePath, publicPath := writeKeyPair(t, key)
+
+		issuer := newActivatedJWT(t, "-jwt-algorithm", alg, "-jwt-private-key-file", privatePath)
+		verifier := newActivatedJWT(t, "-jwt-algorithm", alg, "-jwt-public-key-files", publicPath)
+
+		token, _, err := issuer.IssueToken(context.Background(), "tid", "user-1", 0)
+		if err != nil {
+			t.Fatalf("%s: issue: %v", alg, err)
+		}
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.Header["alg"] != alg || parsed.Header["kid"] != issuer.kid || issuer.kid == "" {
+			t.Fatalf("%s: unexpected header %v", alg, parsed.Header)
+		}
+
+		claims, err := verifier.ParseToken(context.Background(), token)
+		if err != nil {
+			t.Fatalf("%s: verify with public key: %v", alg, err)
+		}
+		if claims.Subject != "user-1" {
+			t.Fatalf("%s: unexpected claims %+v", alg, claims)
+		}
+
+		if _, _, err := verifier.IssueToken(context.Background(), "tid", "user-1", 0); !errors.Is(err, ErrSigningKeyMissing) {
+			t.Fatalf("%s: expected ErrSigningKeyMissing, got %v", alg, err)
+		}
+	}
+}
+
+func TestJWT_RejectsOtherAlgorithm(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privatePath, _ := writeKeyPair(t, key)
+
+	j := newActivatedJWT(t, "-jwt-algorithm", AlgES256, "-jwt-private-key-file", privatePath)
+
+	hs := &jwtx{secret: "this-is-a-very-secure-secret-key-32bytes!", expireTokenInSeconds: 60}
+	token, _, err := hs.IssueToken(context.Background(), "tid", "user-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.ParseToken(context.Background(), token); err == nil {
+		t.Fatal("expected HS256 token to be rejected")
+	}
+}
+
+func TestJWT_Validate_Asymmetric(t *testing.T) {
+	j := &jwtx{alg: AlgRS256, expireTokenInSeconds: 60}
+	if err := j.Validate(); !errors.Is(err, ErrVerifyKeysMissing) {
+		t.Fatalf("expected ErrVerifyKeysMissing, got %v", err)
+	}
+
+	j.alg = "none"
+	if err := j.Validate(); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Fatalf("expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privatePath, _ := writeKeyPair(t, key)
+	j = &jwtx{alg: AlgRS256, privateKeyFile: privatePath}
+	if err := j.loadKeys(); err == nil {
+		t.Fatal("expected error for an EC key with RS256")
+	}
+}
+
+func TestJWT_JWKSHandler(t *testing.T) {
+	current, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	previous, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privatePath, _ := writeKeyPair(t, current)
+	_, previousPublic := writeKeyPair(t, previous)
+
+	j := newActivatedJWT(t,
+		"-jwt-algorithm", AlgES256,
+		"-jwt-private-key-file", privatePath,
+		"-jwt-public-key-files", previousPublic,
+	)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", JWKSHandler(j))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var set JWKSet
+	if err := json.Unmarshal(w.Body.Bytes(), &set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %+v", set)
+	}
+
+	k := set.Keys[0]
+	if k.Kty != "EC" || k.Crv != "P-256" || k.Alg != AlgES256 || k.Use != "sig" || k.Kid != j.kid || len(k.X) != 43 || len(k.Y) != 43 {
+		t.Fatalf("unexpected jwk: %+v", k)
+	}
+
+	hs := &jwtx{secret: "this-is-a-very-secure-secret-key-32bytes!"}
+	if keys := hs.JWKS().Keys; len(keys) != 0 {
+		t.Fatalf("HS256 secret must not be published, got %+v", keys)
+	}
+}
+
+func TestJWT_JWKS_NoDuplicateKeys(t *testing.T) {
+	current, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	previous, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privatePath, currentPublic := writeKeyPair(t, current)
+	_, previousPublic := writeKeyPair(t, previous)
+
+	j := newActivatedJWT(t,
+		"-jwt-algorithm", AlgES256,
+		"-jwt-key-id", "current",
+		"-jwt-private-key-file", privatePath,
+		"-jwt-public-key-files", currentPublic+","+previousPublic,
+	)
+
+	// activating again, e.g. after a restart of the service context
+	if err := j.Activate(nil); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+
+	keys := j.JWKS().Keys
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %+v", keys)
+	}
+	if keys[0].Kid != "current" || keys[1].Kid == "current" {
+		t.Fatalf("unexpected kids: %s, %s", keys[0].Kid, keys[1].Kid)
+	}
+}
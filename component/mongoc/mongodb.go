@@ -24,6 +24,14 @@ type MongoOpt struct {
 	serverSelectionTimeout int
 }
 
+type MongoComponent interface {
+	GetClient() *mongo.Client
+	GetDatabase() *mongo.Database
+	GetCollection(collectionName string) *mongo.Collection
+}
+
+var _ MongoComponent = (*mongoDB)(nil)
+
 type mongoDB struct {
 	id       string
 	prefix   string
@@ -45,56 +53,66 @@ func (mdb *mongoDB) ID() string {
 	return mdb.id
 }
 
-func (mdb *mongoDB) InitFlags() {
+func (mdb *mongoDB) SensitiveFlags() []string {
+	prefix := mdb.prefix
+
+	if prefix != "" {
+		prefix += "-"
+	}
+
+	return []string{fmt.Sprintf("%smongo-uri", prefix)}
+}
+
+func (mdb *mongoDB) InitFlags(fs *flag.FlagSet) {
 	prefix := mdb.prefix
 
 	if prefix != "" {
 		prefix += "-"
 	}
 
-	flag.StringVar(
+	fs.StringVar(
 		&mdb.uri,
 		fmt.Sprintf("%smongo-uri", prefix),
 		"",
 		"MongoDB connection URI",
 	)
 
-	flag.StringVar(
+	fs.StringVar(
 		&mdb.database,
 		fmt.Sprintf("%smongo-database", prefix),
 		"",
 		"MongoDB database name",
 	)
 
-	flag.Uint64Var(
+	fs.Uint64Var(
 		&mdb.maxPoolSize,
 		fmt.Sprintf("%smongo-max-pool-size", prefix),
 		100,
 		"Maximum number of connections in the connection pool - Default 100",
 	)
 
-	flag.Uint64Var(
+	fs.Uint64Var(
 		&mdb.minPoolSize,
 		fmt.Sprintf("%smongo-min-pool-size", prefix),
 		10,
 		"Minimum number of connections in the connection pool - Default 10",
 	)
 
-	flag.IntVar(
+	fs.IntVar(
 		&mdb.maxConnIdleTime,
 		fmt.Sprintf("%smongo-max-conn-idle-time", prefix),
 		300,
 		"Maximum amount of time a connection can remain idle in seconds - Default 300",
 	)
 
-	flag.IntVar(
+	fs.IntVar(
 		&mdb.connectTimeout,
 		fmt.Sprintf("%smongo-connect-timeout", prefix),
 		10,
 		"Connection timeout in seconds - Default 10",
 	)
 
-	flag.IntVar(
+	fs.IntVar(
 		&mdb.serverSelectionTimeout,
 		fmt.Sprintf("%smongo-server-selection-timeout", prefix),
 		30,
@@ -103,26 +121,40 @@ func (mdb *mongoDB) InitFlags() {
 }
 
 func (mdb *mongoDB) Activate(serviceCtx sctx.ServiceContext) error {
+	return mdb.ActivateContext(context.Background(), serviceCtx)
+}
+
+func (mdb *mongoDB) Validate() error {
+	var errs []error
+
+	if mdb.uri == "" {
+		errs = append(errs, errors.New("mongodb uri is required"))
+	}
+	if mdb.database == "" {
+		errs = append(errs, errors.New("mongodb database name is required"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (mdb *mongoDB) ActivateContext(ctx context.Context, serviceCtx sctx.ServiceContext) error {
 	mdb.logger = serviceCtx.Logger(mdb.id)
 	mdb.logLevel = serviceCtx.LogLevel()
 
 	mdb.logger.Infof(
 		"mongodb initialized (uri=%s, database=%s, log_level=%s)",
-		mdb.uri,
+		sctx.RedactDSN(mdb.uri),
 		mdb.database,
 		mdb.logLevel,
 	)
 
-	if mdb.database == "" {
-		return errors.New("mongodb database name is required")
-	}
-
 	client, err := mdb.connect()
 	if err != nil {
 		return err
 	}
 
-	if err := client.Ping(context.Background(), readpref.Primary()); err != nil {
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		_ = client.Disconnect(context.Background())
 		return err
 	}
 
@@ -133,18 +165,30 @@ func (mdb *mongoDB) Activate(serviceCtx sctx.ServiceContext) error {
 }
 
 func (mdb *mongoDB) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return mdb.StopContext(ctx)
+}
+
+func (mdb *mongoDB) StopContext(ctx context.Context) error {
 	if mdb.client == nil {
 		return nil
 	}
 
 	mdb.logger.Info("closing mongodb connection...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
 	return mdb.client.Disconnect(ctx)
 }
 
+func (mdb *mongoDB) HealthCheck(ctx context.Context) error {
+	if mdb.client == nil {
+		return errors.New("mongodb is not connected")
+	}
+
+	return mdb.client.Ping(ctx, readpref.Primary())
+}
+
 func (mdb *mongoDB) GetClient() *mongo.Client {
 	return mdb.client
 }
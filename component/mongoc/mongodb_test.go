@@ -37,7 +37,7 @@ func TestMongoDB_Connect_Success(t *testing.T) {
 }
 
 func TestMongoDB_InsertFind_Success(t *testing.T) {
-	mongoComp := testServiceCtx.MustGet("mongodb").(*mongoDB)
+	mongoComp := sctx.MustGetAs[MongoComponent](testServiceCtx, "mongodb")
 	col := mongoComp.GetCollection("users")
 
 	ctx := context.Background()
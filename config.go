@@ -0,0 +1,212 @@
+package sctx
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Configuration layers, from lowest to highest precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+const configFileFlag = "config-file"
+
+// loadConfigFile reads a YAML, JSON or TOML file and flattens it into flag
+// names. Nested keys are joined with "-" and "_" is treated as "-", so
+// {"db": {"max_conn": 10}} sets the db-max-conn flag.
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %s (allowed: yaml | json | toml)", path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	flattenConfig("", raw, values)
+
+	return values, nil
+}
+
+func flattenConfig(prefix string, raw map[string]interface{}, out map[string]string) {
+	for k, v := range raw {
+		key := strings.ToLower(strings.ReplaceAll(k, "_", "-"))
+		if prefix != "" {
+			key = prefix + "-" + key
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenConfig(key, nested, out)
+			continue
+		}
+
+		out[key] = configValue(v)
+	}
+}
+
+func configValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, 0, len(t))
+		for _, item := range t {
+			items = append(items, configValue(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// applyConfigFile sets every flag found in the config file and fails on keys
+// that do not match any flag, so typos in deploy manifests are caught early.
+func (s *serviceCtx) applyConfigFile(path string) error {
+	values, err := loadConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	var unknown []string
+
+	for key, value := range values {
+		if s.flagSet.Lookup(key) == nil {
+			unknown = append(unknown, key)
+			continue
+		}
+
+		if err := s.flagSet.Set(key, value); err != nil {
+			return fmt.Errorf("invalid value %q for %s: %v", value, key, err)
+		}
+		s.sources[key] = SourceFile
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown config keys: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// loadEnvFile exports the variables of the env file that are not already
+// set in the process environment. Exported keys are remembered so a reload
+// can update them, or unset them once they are removed from the file.
+func (s *serviceCtx) loadEnvFile() error {
+	values := make(map[string]string)
+
+	if _, err := os.Stat(s.envFile); err == nil {
+		if values, err = godotenv.Read(s.envFile); err != nil {
+			return err
+		}
+	}
+
+	for key := range s.envFileKeys {
+		if _, ok := values[key]; !ok {
+			_ = os.Unsetenv(key)
+			delete(s.envFileKeys, key)
+		}
+	}
+
+	for key, value := range values {
+		if _, exists := os.LookupEnv(key); exists && !s.envFileKeys[key] {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+		s.envFileKeys[key] = true
+	}
+
+	return nil
+}
+
+// configFilePath resolves the config file before flags are parsed: the
+// command line wins over the CONFIG_FILE env var.
+func (s *serviceCtx) configFilePath() string {
+	if v, ok := lookupArg(s.args, configFileFlag); ok {
+		return v
+	}
+	return os.Getenv(flagEnvKey(configFileFlag))
+}
+
+// lookupArg finds the value of -name / --name in args, in either the
+// "-name value" or "-name=value" form.
+func lookupArg(args []string, name string) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+
+		trimmed := strings.TrimLeft(arg, "-")
+		if trimmed == arg {
+			continue
+		}
+
+		if trimmed == name {
+			if i+1 < len(args) {
+				return args[i+1], true
+			}
+			return "", true
+		}
+
+		if v, ok := strings.CutPrefix(trimmed, name+"="); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+func flagEnvKey(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// value returns the current value of f, including the one applied by Reload.
+func (s *serviceCtx) value(f *flag.Flag) string {
+	if v, ok := s.reloaded[f.Name]; ok {
+		return v
+	}
+	return f.Value.String()
+}
+
+func (s *serviceCtx) source(f *flag.Flag) string {
+	if src, ok := s.sources[f.Name]; ok {
+		return src
+	}
+	return SourceDefault
+}
@@ -0,0 +1,85 @@
+package sctx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewServiceContext_ConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+app:
+  env: stg
+  stop_timeout: 5s
+  health-timeout: 2s
+`)
+	t.Setenv("APP_STOP_TIMEOUT", "7s")
+
+	s := NewServiceContext(
+		WithArgs("-config-file", path, "-app-health-timeout=3s"),
+	).(*serviceCtx)
+
+	cases := []struct {
+		name   string
+		got    interface{}
+		want   interface{}
+		source string
+	}{
+		{"app-env", s.env, StgEnv, SourceFile},
+		{"app-stop-timeout", s.stopTimeout, 7 * time.Second, SourceEnv},
+		{"app-health-timeout", s.healthTimeout, 3 * time.Second, SourceFlag},
+		{"app-start-timeout", s.startTimeout, time.Minute, SourceDefault},
+	}
+
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+
+		if src := s.source(s.flagSet.Lookup(c.name)); src != c.source {
+			t.Errorf("%s: expected source %s, got %s", c.name, c.source, src)
+		}
+	}
+}
+
+func TestLoadConfigFile_Formats(t *testing.T) {
+	files := map[string]string{
+		"config.json": `{"gin": {"port": 4000, "mode": "release"}, "log_level": "warn"}`,
+		"config.toml": "log-level = \"warn\"\n[gin]\nport = 4000\nmode = \"release\"\n",
+		"config.yml":  "gin:\n  port: 4000\n  mode: release\nlog_level: warn\n",
+	}
+
+	for name, content := range files {
+		values, err := loadConfigFile(writeConfigFile(t, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		for key, want := range map[string]string{"gin-port": "4000", "gin-mode": "release", "log-level": "warn"} {
+			if values[key] != want {
+				t.Errorf("%s: expected %s=%s, got %q", name, key, want, values[key])
+			}
+		}
+	}
+}
+
+func TestApplyConfigFile_UnknownKey(t *testing.T) {
+	s := NewServiceContext(WithArgs()).(*serviceCtx)
+
+	err := s.applyConfigFile(writeConfigFile(t, "config.yaml", "app-evn: prd\n"))
+	if err == nil || !strings.Contains(err.Error(), "unknown config keys: app-evn") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
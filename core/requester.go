@@ -1,6 +1,9 @@
 package core
 
-import "context"
+import (
+	"context"
+	"slices"
+)
 
 const KeyRequester = "requester"
 
@@ -9,9 +12,20 @@ type Requester interface {
 	GetTokenId() string
 }
 
+// AuthorizedRequester is implemented by requesters carrying the roles and
+// scopes granted by their token. Use RequesterRoles and RequesterScopes to
+// read them from any Requester.
+type AuthorizedRequester interface {
+	Requester
+	GetRoles() []string
+	GetScopes() []string
+}
+
 type requesterData struct {
-	Sub string `json:"user_id"`
-	Tid string `json:"tid"`
+	Sub    string   `json:"user_id"`
+	Tid    string   `json:"tid"`
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 func NewRequester(sub, tid string) *requesterData {
@@ -29,6 +43,46 @@ func (r *requesterData) GetTokenId() string {
 	return r.Tid
 }
 
+func (r *requesterData) GetRoles() []string {
+	return r.Roles
+}
+
+func (r *requesterData) GetScopes() []string {
+	return r.Scopes
+}
+
+func (r *requesterData) WithRoles(roles ...string) *requesterData {
+	r.Roles = roles
+	return r
+}
+
+func (r *requesterData) WithScopes(scopes ...string) *requesterData {
+	r.Scopes = scopes
+	return r
+}
+
+func RequesterRoles(r Requester) []string {
+	if ar, ok := r.(AuthorizedRequester); ok {
+		return ar.GetRoles()
+	}
+	return nil
+}
+
+func RequesterScopes(r Requester) []string {
+	if ar, ok := r.(AuthorizedRequester); ok {
+		return ar.GetScopes()
+	}
+	return nil
+}
+
+func HasRole(r Requester, role string) bool {
+	return slices.Contains(RequesterRoles(r), role)
+}
+
+func HasScope(r Requester, scope string) bool {
+	return slices.Contains(RequesterScopes(r), scope)
+}
+
 func GetRequester(ctx context.Context) Requester {
 	if requester, ok := ctx.Value(KeyRequester).(Requester); ok {
 		return requester
@@ -0,0 +1,76 @@
+package sctx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependentComponent is implemented by components that require other
+// components to be activated before them.
+type DependentComponent interface {
+	Dependencies() []string
+}
+
+const (
+	unvisited = iota
+	visiting
+	visited
+)
+
+// sortComponents orders components so that every component comes after the
+// components it depends on. Components without a dependency between them keep
+// their registration order.
+func sortComponents(components []Component, store map[string]Component) ([]Component, error) {
+	sorted := make([]Component, 0, len(components))
+	state := make(map[string]int, len(components))
+	var path []string
+
+	var visit func(c Component) error
+	visit = func(c Component) error {
+		id := c.ID()
+
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == id {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), id)
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+
+		if dc, ok := c.(DependentComponent); ok {
+			for _, depID := range dc.Dependencies() {
+				dep, ok := store[depID]
+				if !ok {
+					return fmt.Errorf("component %s depends on unknown component %s", id, depID)
+				}
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+		sorted = append(sorted, c)
+
+		return nil
+	}
+
+	for _, c := range components {
+		if err := visit(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
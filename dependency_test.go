@@ -0,0 +1,119 @@
+package sctx
+
+import (
+	"flag"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeComponent struct {
+	id          string
+	deps        []string
+	activateErr error
+	activateFor time.Duration
+	stopErr     error
+	events      *[]string
+}
+
+func (f *fakeComponent) ID() string                { return f.id }
+func (f *fakeComponent) InitFlags(_ *flag.FlagSet) {}
+func (f *fakeComponent) Dependencies() []string    { return f.deps }
+
+func (f *fakeComponent) Activate(_ ServiceContext) error {
+	f.record("activate")
+	time.Sleep(f.activateFor)
+	return f.activateErr
+}
+
+func (f *fakeComponent) Stop() error {
+	f.record("stop")
+	return f.stopErr
+}
+
+// eventsMu guards the events of fake components, which may be recorded by a
+// component still activating after a timeout.
+var eventsMu sync.Mutex
+
+func (f *fakeComponent) record(event string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	if f.events != nil {
+		*f.events = append(*f.events, event+" "+f.id)
+	}
+}
+
+func lastEvent(events *[]string) string {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	if len(*events) == 0 {
+		return ""
+	}
+	return (*events)[len(*events)-1]
+}
+
+func newStore(components ...Component) ([]Component, map[string]Component) {
+	store := make(map[string]Component, len(components))
+	for _, c := range components {
+		store[c.ID()] = c
+	}
+	return components, store
+}
+
+func TestSortComponents_DependenciesFirst(t *testing.T) {
+	components, store := newStore(
+		&fakeComponent{id: "repo", deps: []string{"db", "cache"}},
+		&fakeComponent{id: "gin"},
+		&fakeComponent{id: "cache", deps: []string{"db"}},
+		&fakeComponent{id: "db"},
+	)
+
+	sorted, err := sortComponents(components, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, 0, len(sorted))
+	for _, c := range sorted {
+		ids = append(ids, c.ID())
+	}
+
+	if got := strings.Join(ids, ","); got != "db,cache,repo,gin" {
+		t.Fatalf("unexpected order: %s", got)
+	}
+}
+
+func TestSortComponents_Cycle(t *testing.T) {
+	components, store := newStore(
+		&fakeComponent{id: "a", deps: []string{"b"}},
+		&fakeComponent{id: "b", deps: []string{"c"}},
+		&fakeComponent{id: "c", deps: []string{"a"}},
+	)
+
+	_, err := sortComponents(components, store)
+	if err == nil {
+		t.Fatal("expected cycle error")
+	}
+
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSortComponents_MissingDependency(t *testing.T) {
+	components, store := newStore(
+		&fakeComponent{id: "repo", deps: []string{"db"}},
+	)
+
+	_, err := sortComponents(components, store)
+	if err == nil {
+		t.Fatal("expected missing dependency error")
+	}
+
+	if !strings.Contains(err.Error(), "unknown component db") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
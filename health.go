@@ -0,0 +1,86 @@
+package sctx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthChecker is implemented by components that can report whether their
+// backing resources (database, broker, ...) are reachable.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+type ComponentHealth struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency"`
+}
+
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+func (r HealthReport) Healthy() bool {
+	return r.Status == HealthStatusUp
+}
+
+// Health runs the checks of every activated HealthChecker concurrently,
+// bounded by app-health-timeout, and reports the result per component.
+func (s *serviceCtx) Health(ctx context.Context) HealthReport {
+	ctx, cancel := withOptionalTimeout(ctx, s.healthTimeout)
+	defer cancel()
+
+	report := HealthReport{
+		Status:     HealthStatusUp,
+		Components: make(map[string]ComponentHealth),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, c := range s.activatedComponents() {
+		hc, ok := c.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string, hc HealthChecker) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := runWithContext(ctx, func() error { return hc.HealthCheck(ctx) })
+
+			health := ComponentHealth{
+				Status:  HealthStatusUp,
+				Latency: time.Since(start).String(),
+			}
+			if err != nil {
+				health.Status = HealthStatusDown
+				health.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Components[id] = health
+			if err != nil {
+				report.Status = HealthStatusDown
+			}
+		}(c.ID(), hc)
+	}
+
+	wg.Wait()
+
+	return report
+}
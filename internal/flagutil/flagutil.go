@@ -0,0 +1,17 @@
+// Package flagutil holds helpers shared by the flags of the service context,
+// its loggers and components.
+package flagutil
+
+import "strings"
+
+// SplitList splits a comma-separated flag value, trimming spaces and dropping
+// empty items.
+func SplitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
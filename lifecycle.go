@@ -0,0 +1,116 @@
+package sctx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ContextActivator is implemented by components that can honor a deadline
+// or cancellation while activating. When present it is used instead of
+// Component.Activate.
+type ContextActivator interface {
+	ActivateContext(ctx context.Context, serviceCtx ServiceContext) error
+}
+
+// ContextStopper is implemented by components that can honor a deadline
+// or cancellation while stopping. When present it is used instead of
+// Component.Stop.
+type ContextStopper interface {
+	StopContext(ctx context.Context) error
+}
+
+func (s *serviceCtx) activateComponent(ctx context.Context, c Component) error {
+	ctx, cancel := withOptionalTimeout(ctx, s.componentStartTimeout)
+	defer cancel()
+
+	if ca, ok := c.(ContextActivator); ok {
+		return ca.ActivateContext(ctx, s)
+	}
+
+	// a legacy component finishing after the timeout is never added to the
+	// activated list, so stop it here to release what it acquired
+	return runWithContextThen(ctx, func() error { return c.Activate(s) }, func(err error) {
+		if err != nil {
+			return
+		}
+
+		s.logger.Warnf("component %s activated after timeout, stopping it", c.ID())
+		if err := c.Stop(); err != nil {
+			s.logger.Errorf("stop %s: %v", c.ID(), err)
+		}
+	})
+}
+
+func (s *serviceCtx) stopComponent(ctx context.Context, c Component) error {
+	ctx, cancel := withOptionalTimeout(ctx, s.componentStopTimeout)
+	defer cancel()
+
+	if cs, ok := c.(ContextStopper); ok {
+		return cs.StopContext(ctx)
+	}
+
+	return runWithContext(ctx, c.Stop)
+}
+
+// stopActivated stops every activated component in reverse order. It keeps
+// going when a component fails and returns all errors encountered.
+func (s *serviceCtx) stopActivated(ctx context.Context, action string) []error {
+	s.mu.Lock()
+	activated := s.activated
+	s.activated = nil
+	s.mu.Unlock()
+
+	var errs []error
+
+	for i := len(activated) - 1; i >= 0; i-- {
+		c := activated[i]
+		s.logger.Infof("%s component: %s", action, c.ID())
+		if err := s.stopComponent(ctx, c); err != nil {
+			s.logger.Errorf("%s %s: %v", action, c.ID(), err)
+			errs = append(errs, fmt.Errorf("%s %s: %w", action, c.ID(), err))
+		}
+	}
+	return errs
+}
+
+func (s *serviceCtx) activatedComponents() []Component {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Component(nil), s.activated...)
+}
+
+// runWithContext runs fn and returns its result, or the context error if ctx
+// is done first. fn keeps running in the background in the latter case, so
+// it is only a guard against components that ignore their context.
+func runWithContext(ctx context.Context, fn func() error) error {
+	return runWithContextThen(ctx, fn, nil)
+}
+
+// runWithContextThen is runWithContext, with late, when not nil, receiving
+// the result of fn if it finishes after ctx is done.
+func runWithContextThen(ctx context.Context, fn func() error, late func(err error)) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if late != nil {
+			go func() { late(<-done) }()
+		}
+		return ctx.Err()
+	}
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -1,7 +1,9 @@
 package logger
 
+import "flag"
+
 type AppLogger interface {
-	InitFlags()
+	InitFlags(fs *flag.FlagSet)
 	Activate() error
 	Stop() error
 
@@ -9,3 +11,12 @@ type AppLogger interface {
 	GetLevel() string
 	SetLevel(string) error
 }
+
+// PrefixLeveler is implemented by AppLoggers that support overriding the
+// level of the loggers created for a given prefix.
+type PrefixLeveler interface {
+	GetPrefixLevels() map[string]string
+	GetPrefixLevel(prefix string) (string, bool)
+	SetPrefixLevel(prefix, level string) error
+	ResetPrefixLevel(prefix string)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import "context"
+
+type loggerKey struct{}
+
+type fieldsKey struct{}
+
+// WithContext returns a copy of ctx carrying l, see FromContext.
+func WithContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored by WithContext. When there is none it
+// returns fallback, if given, and otherwise a logger DISCARDING every entry:
+// code that may run outside of a request, e.g. in background jobs, should pass
+// its own logger as fallback.
+func FromContext(ctx context.Context, fallback ...Logger) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+	if len(fallback) > 0 && fallback[0] != nil {
+		return fallback[0]
+	}
+	return Nop()
+}
+
+// ContextWithFields returns a copy of ctx carrying fields, merged with the
+// ones already in ctx. They are added to every entry logged by the *Ctx
+// methods.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	merged := make(Fields, len(fields))
+	for k, v := range FieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the fields stored by ContextWithFields.
+func FieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	return fields
+}
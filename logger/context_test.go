@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext_DefaultsToNop(t *testing.T) {
+	if _, ok := FromContext(context.Background()).(nopLogger); !ok {
+		t.Fatal("expected nop logger")
+	}
+
+	l := Nop().With("k", "v")
+	ctx := WithContext(context.Background(), l)
+	if FromContext(ctx) != l {
+		t.Fatal("expected stored logger")
+	}
+}
+
+func TestFromContext_Fallback(t *testing.T) {
+	fallback := Nop().With("k", "fallback")
+	if FromContext(context.Background(), fallback) != fallback {
+		t.Fatal("expected fallback logger")
+	}
+
+	l := Nop().With("k", "v")
+	ctx := WithContext(context.Background(), l)
+	if FromContext(ctx, fallback) != l {
+		t.Fatal("expected stored logger over fallback")
+	}
+}
+
+func TestContextWithFields_Merges(t *testing.T) {
+	parent := ContextWithFields(context.Background(), Fields{"request_id": "1", "path": "/a"})
+	child := ContextWithFields(parent, Fields{"path": "/b", "requester": "u1"})
+
+	got := FieldsFromContext(child)
+	if len(got) != 3 || got["request_id"] != "1" || got["path"] != "/b" || got["requester"] != "u1" {
+		t.Fatalf("unexpected fields: %v", got)
+	}
+
+	if FieldsFromContext(parent)["path"] != "/a" {
+		t.Fatal("parent fields must not change")
+	}
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DatLe328/service-context/internal/flagutil"
+)
+
+// Levels keeps the global level of an AppLogger and the per-prefix overrides
+// of PrefixLeveler, so that backends only have to filter entries. Levels are
+// stored as ints in the scale of the backend, parse and format convert them
+// from and to level names.
+//
+// Embedding *Levels provides GetLevel, SetLevel and the PrefixLeveler methods.
+type Levels struct {
+	parse  func(level string) (int, error)
+	format func(level int) string
+
+	mu       sync.Mutex
+	name     string
+	global   atomic.Int64
+	prefixes map[string]*PrefixLevel
+}
+
+func NewLevels(parse func(level string) (int, error), format func(level int) string) *Levels {
+	return &Levels{
+		parse:    parse,
+		format:   format,
+		prefixes: make(map[string]*PrefixLevel),
+	}
+}
+
+// Flag binds log-level to the guarded level name, which admin endpoints may
+// change while the flag set is read by Reload. The name is only checked by
+// SetLevel, when the AppLogger is activated.
+func (l *Levels) Flag() flag.Value {
+	return levelFlag{l}
+}
+
+type levelFlag struct {
+	levels *Levels
+}
+
+func (f levelFlag) String() string {
+	if f.levels == nil {
+		return ""
+	}
+	return f.levels.GetLevel()
+}
+
+func (f levelFlag) Set(level string) error {
+	f.levels.mu.Lock()
+	defer f.levels.mu.Unlock()
+
+	f.levels.name = level
+	return nil
+}
+
+// Level returns the global level.
+func (l *Levels) Level() int {
+	return int(l.global.Load())
+}
+
+func (l *Levels) GetLevel() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.name
+}
+
+func (l *Levels) SetLevel(level string) error {
+	lv, err := l.parse(level)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.name = level
+	l.global.Store(int64(lv))
+	return nil
+}
+
+// Prefix returns the level of prefix, creating it on first use so that
+// loggers created before an override still pick it up.
+func (l *Levels) Prefix(prefix string) *PrefixLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pl, ok := l.prefixes[prefix]
+	if !ok {
+		pl = &PrefixLevel{global: &l.global}
+		l.prefixes[prefix] = pl
+	}
+	return pl
+}
+
+func (l *Levels) GetPrefixLevels() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	levels := make(map[string]string)
+	for prefix, pl := range l.prefixes {
+		if pl.override.Load() {
+			levels[prefix] = l.format(int(pl.level.Load()))
+		}
+	}
+	return levels
+}
+
+// GetPrefixLevel returns the override of prefix, if any.
+func (l *Levels) GetPrefixLevel(prefix string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pl, ok := l.prefixes[prefix]
+	if !ok || !pl.override.Load() {
+		return "", false
+	}
+	return l.format(int(pl.level.Load())), true
+}
+
+func (l *Levels) SetPrefixLevel(prefix, level string) error {
+	lv, err := l.parse(level)
+	if err != nil {
+		return err
+	}
+
+	pl := l.Prefix(prefix)
+	pl.level.Store(int64(lv))
+	pl.override.Store(true)
+	return nil
+}
+
+func (l *Levels) ResetPrefixLevel(prefix string) {
+	l.Prefix(prefix).override.Store(false)
+}
+
+// SetPrefixLevels sets the overrides of log-levels, e.g. "gorm=warn,mongo=debug".
+func (l *Levels) SetPrefixLevels(s string) error {
+	for _, pair := range flagutil.SplitList(s) {
+		prefix, level, ok := strings.Cut(pair, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || prefix == "" {
+			return fmt.Errorf("invalid log level override %q (expected prefix=level)", pair)
+		}
+		if err := l.SetPrefixLevel(prefix, strings.TrimSpace(level)); err != nil {
+			return fmt.Errorf("invalid log level override %q: %v", pair, err)
+		}
+	}
+	return nil
+}
+
+// PrefixLevel is the level of the loggers of a prefix. It follows the global
+// level unless an override is set for the prefix.
+type PrefixLevel struct {
+	global   *atomic.Int64
+	level    atomic.Int64
+	override atomic.Bool
+}
+
+func (p *PrefixLevel) Level() int {
+	if p.override.Load() {
+		return int(p.level.Load())
+	}
+	return int(p.global.Load())
+}
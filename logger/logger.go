@@ -1,5 +1,7 @@
 package logger
 
+import "context"
+
 type Fields map[string]interface{}
 
 type Logger interface {
@@ -13,6 +15,31 @@ type Logger interface {
 	Warnf(format string, args ...interface{})
 	Errorf(format string, args ...interface{})
 
+	// The *w methods take alternating keys and values.
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+
+	// Fatal logs then calls os.Exit(1), Panic logs then panics.
+	Fatal(args ...interface{})
+	Fatalf(format string, args ...interface{})
+	Panic(args ...interface{})
+	Panicf(format string, args ...interface{})
+
+	// The *Ctx methods add the fields stored in ctx by ContextWithFields.
+	DebugCtx(ctx context.Context, args ...interface{})
+	InfoCtx(ctx context.Context, args ...interface{})
+	WarnCtx(ctx context.Context, args ...interface{})
+	ErrorCtx(ctx context.Context, args ...interface{})
+
+	DebugfCtx(ctx context.Context, format string, args ...interface{})
+	InfofCtx(ctx context.Context, format string, args ...interface{})
+	WarnfCtx(ctx context.Context, format string, args ...interface{})
+	ErrorfCtx(ctx context.Context, format string, args ...interface{})
+
 	With(key string, value interface{}) Logger
 	WithFields(fields Fields) Logger
+	// WithContext returns a logger with the fields stored in ctx.
+	WithContext(ctx context.Context) Logger
 }
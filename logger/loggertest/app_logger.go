@@ -0,0 +1,199 @@
+// Package loggertest provides an AppLogger recording entries in memory, to
+// assert in tests what a component logged. Inject it with sctx.WithAppLogger.
+package loggertest
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DatLe328/service-context/logger"
+)
+
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+	LevelPanic = "panic"
+	LevelFatal = "fatal"
+)
+
+type Entry struct {
+	Level   string
+	Message string
+	Prefix  string
+	Fields  logger.Fields
+}
+
+func (e Entry) String() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s [%s] %s", e.Level, e.Prefix, e.Message)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+	}
+	return b.String()
+}
+
+// AppLogger records every entry at or above its level. Fatal and Panic record
+// the entry then panic, so that tests can recover instead of exiting.
+type AppLogger struct {
+	mu       sync.Mutex
+	level    string
+	prefixes map[string]string
+	entries  []Entry
+}
+
+// New returns an AppLogger recording entries of every level.
+func New() *AppLogger {
+	return &AppLogger{
+		level:    LevelDebug,
+		prefixes: make(map[string]string),
+	}
+}
+
+func (a *AppLogger) InitFlags(fs *flag.FlagSet) {
+	fs.Var(levelFlag{a}, "log-level", "Log level: debug | info | warn | error")
+}
+
+type levelFlag struct {
+	app *AppLogger
+}
+
+func (f levelFlag) String() string {
+	if f.app == nil {
+		return ""
+	}
+	return f.app.GetLevel()
+}
+
+func (f levelFlag) Set(level string) error {
+	return f.app.SetLevel(level)
+}
+
+func (a *AppLogger) Activate() error { return nil }
+func (a *AppLogger) Stop() error     { return nil }
+
+func (a *AppLogger) GetLogger(prefix string) logger.Logger {
+	return &recordLogger{app: a, prefix: prefix}
+}
+
+func (a *AppLogger) GetLevel() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.level
+}
+
+func (a *AppLogger) SetLevel(level string) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.level = level
+	return nil
+}
+
+func (a *AppLogger) GetPrefixLevels() map[string]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	levels := make(map[string]string, len(a.prefixes))
+	for prefix, level := range a.prefixes {
+		levels[prefix] = level
+	}
+	return levels
+}
+
+func (a *AppLogger) GetPrefixLevel(prefix string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	level, ok := a.prefixes[prefix]
+	return level, ok
+}
+
+func (a *AppLogger) SetPrefixLevel(prefix, level string) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.prefixes[prefix] = level
+	return nil
+}
+
+func (a *AppLogger) ResetPrefixLevel(prefix string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.prefixes, prefix)
+}
+
+// Entries returns a copy of the recorded entries, oldest first.
+func (a *AppLogger) Entries() []Entry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]Entry(nil), a.entries...)
+}
+
+// Filter returns the recorded entries of level whose message contains msg.
+// An empty level or msg matches every entry.
+func (a *AppLogger) Filter(level, msg string) []Entry {
+	var matches []Entry
+	for _, e := range a.Entries() {
+		if (level == "" || e.Level == level) && strings.Contains(e.Message, msg) {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// Reset drops the recorded entries.
+func (a *AppLogger) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.entries = nil
+}
+
+func (a *AppLogger) enabled(prefix, level string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	threshold := a.level
+	if v, ok := a.prefixes[prefix]; ok {
+		threshold = v
+	}
+	return logger.ParseSlogLevel(level) >= logger.ParseSlogLevel(threshold)
+}
+
+func (a *AppLogger) record(e Entry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.entries = append(a.entries, e)
+}
+
+func checkLevel(level string) error {
+	switch level {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError, LevelPanic, LevelFatal:
+		return nil
+	default:
+		return fmt.Errorf("unrecognized level: %q", level)
+	}
+}
@@ -0,0 +1,47 @@
+package loggertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DatLe328/service-context/logger"
+)
+
+func TestAppLogger_Records(t *testing.T) {
+	app := New()
+	if err := app.SetPrefixLevel("gorm", LevelWarn); err != nil {
+		t.Fatalf("set prefix level: %v", err)
+	}
+
+	ctx := logger.ContextWithFields(context.Background(), logger.Fields{"request_id": "r1"})
+
+	app.GetLogger("gorm").Info("hidden")
+	app.GetLogger("gorm").Warnf("slow query %dms", 250)
+	app.GetLogger("app").With("tenant", "t1").InfoCtx(ctx, "handled")
+	app.GetLogger("app").Errorw("failed", "user_id", 42)
+
+	app.AssertNotLogged(t, LevelInfo, "hidden")
+	app.AssertLogged(t, LevelWarn, "slow query 250ms")
+
+	e := app.AssertLogged(t, LevelInfo, "handled")
+	e.AssertField(t, "tenant", "t1")
+	e.AssertField(t, "request_id", "r1")
+
+	app.AssertLogged(t, LevelError, "failed").AssertField(t, "user_id", 42)
+	app.AssertCount(t, "", "", 3)
+
+	app.Reset()
+	app.AssertCount(t, "", "", 0)
+}
+
+func TestAppLogger_PanicRecords(t *testing.T) {
+	app := New()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+		app.AssertLogged(t, LevelFatal, "cannot start")
+	}()
+	app.GetLogger("app").Fatalf("cannot %s", "start")
+}
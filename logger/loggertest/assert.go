@@ -0,0 +1,66 @@
+package loggertest
+
+import (
+	"strings"
+	"testing"
+)
+
+// AssertLogged fails the test unless an entry of level whose message contains
+// msg was recorded. It returns the first match.
+func (a *AppLogger) AssertLogged(t testing.TB, level, msg string) Entry {
+	t.Helper()
+
+	matches := a.Filter(level, msg)
+	if len(matches) == 0 {
+		t.Fatalf("expected a %s entry containing %q, got:\n%s", level, msg, a.dump())
+		return Entry{}
+	}
+	return matches[0]
+}
+
+// AssertNotLogged fails the test if an entry of level whose message contains
+// msg was recorded.
+func (a *AppLogger) AssertNotLogged(t testing.TB, level, msg string) {
+	t.Helper()
+
+	if matches := a.Filter(level, msg); len(matches) > 0 {
+		t.Fatalf("expected no %s entry containing %q, got:\n%s", level, msg, a.dump())
+	}
+}
+
+// AssertCount fails the test unless exactly n entries of level whose message
+// contains msg were recorded.
+func (a *AppLogger) AssertCount(t testing.TB, level, msg string, n int) {
+	t.Helper()
+
+	if matches := a.Filter(level, msg); len(matches) != n {
+		t.Fatalf("expected %d %s entries containing %q, got %d:\n%s", n, level, msg, len(matches), a.dump())
+	}
+}
+
+// AssertField fails the test unless the entry has key set to value.
+func (e Entry) AssertField(t testing.TB, key string, value interface{}) {
+	t.Helper()
+
+	got, ok := e.Fields[key]
+	if !ok {
+		t.Fatalf("expected field %q in entry %s", key, e)
+		return
+	}
+	if got != value {
+		t.Fatalf("expected field %q = %v, got %v in entry %s", key, value, got, e)
+	}
+}
+
+func (a *AppLogger) dump() string {
+	entries := a.Entries()
+	if len(entries) == 0 {
+		return "  (no entries)"
+	}
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = "  " + e.String()
+	}
+	return strings.Join(lines, "\n")
+}
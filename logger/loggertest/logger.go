@@ -0,0 +1,146 @@
+package loggertest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DatLe328/service-context/logger"
+)
+
+type recordLogger struct {
+	app    *AppLogger
+	prefix string
+	fields logger.Fields
+}
+
+func (l *recordLogger) Debug(args ...interface{}) { l.log(LevelDebug, fmt.Sprint(args...), nil) }
+func (l *recordLogger) Info(args ...interface{})  { l.log(LevelInfo, fmt.Sprint(args...), nil) }
+func (l *recordLogger) Warn(args ...interface{})  { l.log(LevelWarn, fmt.Sprint(args...), nil) }
+func (l *recordLogger) Error(args ...interface{}) { l.log(LevelError, fmt.Sprint(args...), nil) }
+
+func (l *recordLogger) Debugf(format string, args ...interface{}) {
+	l.log(LevelDebug, fmt.Sprintf(format, args...), nil)
+}
+func (l *recordLogger) Infof(format string, args ...interface{}) {
+	l.log(LevelInfo, fmt.Sprintf(format, args...), nil)
+}
+func (l *recordLogger) Warnf(format string, args ...interface{}) {
+	l.log(LevelWarn, fmt.Sprintf(format, args...), nil)
+}
+func (l *recordLogger) Errorf(format string, args ...interface{}) {
+	l.log(LevelError, fmt.Sprintf(format, args...), nil)
+}
+
+func (l *recordLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.log(LevelDebug, msg, keyValueFields(keysAndValues))
+}
+func (l *recordLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.log(LevelInfo, msg, keyValueFields(keysAndValues))
+}
+func (l *recordLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.log(LevelWarn, msg, keyValueFields(keysAndValues))
+}
+func (l *recordLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.log(LevelError, msg, keyValueFields(keysAndValues))
+}
+
+func (l *recordLogger) Fatal(args ...interface{}) {
+	msg := fmt.Sprint(args...)
+	l.log(LevelFatal, msg, nil)
+	panic(msg)
+}
+func (l *recordLogger) Fatalf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	l.log(LevelFatal, msg, nil)
+	panic(msg)
+}
+func (l *recordLogger) Panic(args ...interface{}) {
+	msg := fmt.Sprint(args...)
+	l.log(LevelPanic, msg, nil)
+	panic(msg)
+}
+func (l *recordLogger) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	l.log(LevelPanic, msg, nil)
+	panic(msg)
+}
+
+func (l *recordLogger) DebugCtx(ctx context.Context, args ...interface{}) {
+	l.log(LevelDebug, fmt.Sprint(args...), logger.FieldsFromContext(ctx))
+}
+func (l *recordLogger) InfoCtx(ctx context.Context, args ...interface{}) {
+	l.log(LevelInfo, fmt.Sprint(args...), logger.FieldsFromContext(ctx))
+}
+func (l *recordLogger) WarnCtx(ctx context.Context, args ...interface{}) {
+	l.log(LevelWarn, fmt.Sprint(args...), logger.FieldsFromContext(ctx))
+}
+func (l *recordLogger) ErrorCtx(ctx context.Context, args ...interface{}) {
+	l.log(LevelError, fmt.Sprint(args...), logger.FieldsFromContext(ctx))
+}
+
+func (l *recordLogger) DebugfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(LevelDebug, fmt.Sprintf(format, args...), logger.FieldsFromContext(ctx))
+}
+func (l *recordLogger) InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(LevelInfo, fmt.Sprintf(format, args...), logger.FieldsFromContext(ctx))
+}
+func (l *recordLogger) WarnfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(LevelWarn, fmt.Sprintf(format, args...), logger.FieldsFromContext(ctx))
+}
+func (l *recordLogger) ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(LevelError, fmt.Sprintf(format, args...), logger.FieldsFromContext(ctx))
+}
+
+func (l *recordLogger) With(key string, value interface{}) logger.Logger {
+	return l.WithFields(logger.Fields{key: value})
+}
+
+func (l *recordLogger) WithFields(fields logger.Fields) logger.Logger {
+	return &recordLogger{
+		app:    l.app,
+		prefix: l.prefix,
+		fields: mergeFields(l.fields, fields),
+	}
+}
+
+func (l *recordLogger) WithContext(ctx context.Context) logger.Logger {
+	return l.WithFields(logger.FieldsFromContext(ctx))
+}
+
+func (l *recordLogger) log(level, msg string, fields logger.Fields) {
+	if !l.app.enabled(l.prefix, level) {
+		return
+	}
+
+	l.app.record(Entry{
+		Level:   level,
+		Message: msg,
+		Prefix:  l.prefix,
+		Fields:  mergeFields(l.fields, fields),
+	})
+}
+
+func mergeFields(base, extra logger.Fields) logger.Fields {
+	merged := make(logger.Fields, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
+
+// keyValueFields converts alternating keys and values, a key without value
+// being recorded under "!BADKEY" like slog does.
+func keyValueFields(keysAndValues []interface{}) logger.Fields {
+	fields := make(logger.Fields, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fields["!BADKEY"] = keysAndValues[i]
+			break
+		}
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return fields
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+type nopLogger struct{}
+
+// Nop returns a logger discarding every entry.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(args ...interface{}) {}
+func (nopLogger) Info(args ...interface{})  {}
+func (nopLogger) Warn(args ...interface{})  {}
+func (nopLogger) Error(args ...interface{}) {}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
+
+func (nopLogger) Debugw(msg string, keysAndValues ...interface{}) {}
+func (nopLogger) Infow(msg string, keysAndValues ...interface{})  {}
+func (nopLogger) Warnw(msg string, keysAndValues ...interface{})  {}
+func (nopLogger) Errorw(msg string, keysAndValues ...interface{}) {}
+
+// Fatal and Panic still exit and panic, as callers rely on it.
+func (nopLogger) Fatal(args ...interface{})                 { os.Exit(1) }
+func (nopLogger) Fatalf(format string, args ...interface{}) { os.Exit(1) }
+func (nopLogger) Panic(args ...interface{})                 { panic(fmt.Sprint(args...)) }
+func (nopLogger) Panicf(format string, args ...interface{}) { panic(fmt.Sprintf(format, args...)) }
+
+func (nopLogger) DebugCtx(ctx context.Context, args ...interface{}) {}
+func (nopLogger) InfoCtx(ctx context.Context, args ...interface{})  {}
+func (nopLogger) WarnCtx(ctx context.Context, args ...interface{})  {}
+func (nopLogger) ErrorCtx(ctx context.Context, args ...interface{}) {}
+
+func (nopLogger) DebugfCtx(ctx context.Context, format string, args ...interface{}) {}
+func (nopLogger) InfofCtx(ctx context.Context, format string, args ...interface{})  {}
+func (nopLogger) WarnfCtx(ctx context.Context, format string, args ...interface{})  {}
+func (nopLogger) ErrorfCtx(ctx context.Context, format string, args ...interface{}) {}
+
+func (l nopLogger) With(key string, value interface{}) Logger { return l }
+func (l nopLogger) WithFields(fields Fields) Logger           { return l }
+func (l nopLogger) WithContext(ctx context.Context) Logger    { return l }
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// NewSlogHandler exposes app as an slog.Handler. Records are written with the
+// logger of prefix and filtered by its level, including per-prefix overrides
+// when app is a PrefixLeveler. The logger of prefix is created once, so build
+// the handler after app is activated.
+func NewSlogHandler(app AppLogger, prefix string) slog.Handler {
+	return &slogHandler{
+		logger: app.GetLogger(prefix),
+		level:  levelOf(app, prefix),
+	}
+}
+
+// NewSlogLogger is a shortcut for slog.New(NewSlogHandler(app, prefix)).
+func NewSlogLogger(app AppLogger, prefix string) *slog.Logger {
+	return slog.New(NewSlogHandler(app, prefix))
+}
+
+type slogHandler struct {
+	logger Logger
+	level  func() string
+	group  string
+}
+
+// levelOf returns how to read the current level of prefix, resolved once per
+// handler instead of once per record.
+func levelOf(app AppLogger, prefix string) func() string {
+	pl, ok := app.(PrefixLeveler)
+	if !ok {
+		return app.GetLevel
+	}
+
+	return func() string {
+		if level, ok := pl.GetPrefixLevel(prefix); ok {
+			return level
+		}
+		return app.GetLevel()
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= ParseSlogLevel(h.level())
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	kv := make([]interface{}, 0, r.NumAttrs()*2)
+	r.Attrs(func(a slog.Attr) bool {
+		kv = appendAttr(kv, h.group, a)
+		return true
+	})
+
+	l := h.logger.WithContext(ctx)
+
+	switch {
+	case r.Level >= slog.LevelError:
+		l.Errorw(r.Message, kv...)
+	case r.Level >= slog.LevelWarn:
+		l.Warnw(r.Message, kv...)
+	case r.Level >= slog.LevelInfo:
+		l.Infow(r.Message, kv...)
+	default:
+		l.Debugw(r.Message, kv...)
+	}
+
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var kv []interface{}
+	for _, a := range attrs {
+		kv = appendAttr(kv, h.group, a)
+	}
+
+	clone := *h
+	for i := 0; i+1 < len(kv); i += 2 {
+		clone.logger = clone.logger.With(kv[i].(string), kv[i+1])
+	}
+	return &clone
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.group = joinKey(h.group, name)
+	return &clone
+}
+
+// appendAttr flattens a into key-value pairs, group keys being joined with
+// dots.
+func appendAttr(kv []interface{}, group string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			group = joinKey(group, a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			kv = appendAttr(kv, group, ga)
+		}
+		return kv
+	}
+
+	if a.Key == "" {
+		return kv
+	}
+	return append(kv, joinKey(group, a.Key), a.Value.Any())
+}
+
+func joinKey(group, key string) string {
+	if group == "" {
+		return key
+	}
+	return group + "." + key
+}
+
+// ParseSlogLevel converts a level name (debug, info, warn, error, dpanic,
+// panic, fatal) to an slog.Level. Unknown names map to slog.LevelInfo.
+func ParseSlogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	case "dpanic", "panic", "fatal":
+		return slog.LevelError + 4
+	default:
+		return slog.LevelInfo
+	}
+}
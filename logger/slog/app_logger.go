@@ -0,0 +1,229 @@
+package sloglogger
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/DatLe328/service-context/internal/flagutil"
+	"github.com/DatLe328/service-context/logger"
+)
+
+const (
+	levelPanic = slog.LevelError + 4
+	levelFatal = slog.LevelError + 8
+)
+
+type appLogger struct {
+	*logger.Levels
+	prefixLevels string
+	encoding     string
+	outputPaths  string
+	handler      slog.Handler
+	files        []*os.File
+}
+
+// NewSlogLogger returns an AppLogger backed by log/slog. It registers the same
+// flags as the zap logger, so that both read the same config files, but log
+// files are not rotated and entries are not sampled: the log-file-* and
+// log-sampling-* flags are accepted and ignored.
+func NewSlogLogger() logger.AppLogger {
+	return &appLogger{
+		Levels:  logger.NewLevels(parseLevel, formatLevel),
+		handler: slog.NewJSONHandler(os.Stderr, nil),
+	}
+}
+
+func (a *appLogger) InitFlags(fs *flag.FlagSet) {
+	fs.Var(
+		a.Flag(),
+		"log-level",
+		"Log level: debug | info | warn | error",
+	)
+	fs.StringVar(
+		&a.prefixLevels,
+		"log-levels",
+		"",
+		"Log level per logger prefix, e.g. gorm=warn,mongo=debug",
+	)
+	fs.StringVar(
+		&a.encoding,
+		"log-encoding",
+		"",
+		"Log encoding: json | text (default text when log level is debug, json otherwise)",
+	)
+	fs.StringVar(
+		&a.outputPaths,
+		"log-output-paths",
+		"stderr",
+		"Comma-separated log outputs: stdout, stderr or file paths",
+	)
+	fs.Int("log-file-max-size", 100, "Ignored, log files are not rotated by the slog logger")
+	fs.Duration("log-file-max-age", 0, "Ignored, log files are not rotated by the slog logger")
+	fs.Int("log-file-max-backups", 0, "Ignored, log files are not rotated by the slog logger")
+	fs.Int("log-sampling-initial", 100, "Ignored, entries are not sampled by the slog logger")
+	fs.Int("log-sampling-thereafter", 100, "Ignored, entries are not sampled by the slog logger")
+}
+
+func (a *appLogger) Activate() error {
+	level := a.GetLevel()
+	if level == "" {
+		return errors.New("log level cannot be empty")
+	}
+
+	if err := a.SetLevel(level); err != nil {
+		return err
+	}
+	lv := slog.Level(a.Level())
+
+	if err := a.SetPrefixLevels(a.prefixLevels); err != nil {
+		return err
+	}
+
+	w, files, err := openOutputs(flagutil.SplitList(a.outputPaths))
+	if err != nil {
+		return err
+	}
+
+	// levels are filtered per prefix by levelHandler
+	opts := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       slog.LevelDebug,
+		ReplaceAttr: replaceLevelName,
+	}
+
+	encoding := a.encoding
+	if encoding == "" {
+		encoding = "json"
+		if lv == slog.LevelDebug {
+			encoding = "text"
+		}
+	}
+
+	switch encoding {
+	case "json":
+		a.handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		a.handler = slog.NewTextHandler(w, opts)
+	default:
+		_ = closeFiles(files)
+		return fmt.Errorf("invalid log encoding: %s (allowed: json | text)", encoding)
+	}
+
+	previous := a.files
+	a.files = files
+	return closeFiles(previous)
+}
+
+func (a *appLogger) GetLogger(prefix string) logger.Logger {
+	return &slogLogger{
+		handler: &levelHandler{
+			Handler: a.handler.WithAttrs([]slog.Attr{slog.String("prefix", prefix)}),
+			leveler: prefixLeveler{a.Prefix(prefix)},
+		},
+	}
+}
+
+func (a *appLogger) Stop() error {
+	files := a.files
+	a.files = nil
+	return closeFiles(files)
+}
+
+// prefixLeveler reads the level of a prefix as a slog.Leveler.
+type prefixLeveler struct {
+	level *logger.PrefixLevel
+}
+
+func (l prefixLeveler) Level() slog.Level {
+	return slog.Level(l.level.Level())
+}
+
+// levelHandler replaces the level check of the wrapped handler, which is
+// built with every level enabled.
+type levelHandler struct {
+	slog.Handler
+	leveler slog.Leveler
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.leveler.Level()
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), leveler: h.leveler}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), leveler: h.leveler}
+}
+
+func parseLevel(level string) (int, error) {
+	var lv slog.Level
+	if err := lv.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unrecognized level: %q", level)
+	}
+	return int(lv), nil
+}
+
+func formatLevel(level int) string {
+	return strings.ToLower(slog.Level(level).String())
+}
+
+func replaceLevelName(_ []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey {
+		return a
+	}
+
+	switch a.Value.Any() {
+	case levelPanic:
+		a.Value = slog.StringValue("PANIC")
+	case levelFatal:
+		a.Value = slog.StringValue("FATAL")
+	}
+	return a
+}
+
+// openOutputs opens every output path. "stdout" and "stderr" are the standard
+// streams, anything else is a file path opened in append mode.
+func openOutputs(paths []string) (io.Writer, []*os.File, error) {
+	if len(paths) == 0 {
+		return nil, nil, errors.New("log output paths cannot be empty")
+	}
+
+	var (
+		writers []io.Writer
+		files   []*os.File
+	)
+
+	for _, path := range paths {
+		switch path {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		default:
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, nil, errors.Join(fmt.Errorf("open log file %s: %w", path, err), closeFiles(files))
+			}
+			files = append(files, f)
+			writers = append(writers, f)
+		}
+	}
+
+	return io.MultiWriter(writers...), files, nil
+}
+
+func closeFiles(files []*os.File) error {
+	var errs []error
+	for _, f := range files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
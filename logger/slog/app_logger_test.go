@@ -0,0 +1,125 @@
+package sloglogger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DatLe328/service-context/logger"
+)
+
+func newTestLogger(t *testing.T, args ...string) (*appLogger, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	a := NewSlogLogger().(*appLogger)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	a.InitFlags(fs)
+	if err := fs.Parse(append([]string{"-log-output-paths", path, "-log-encoding", "json"}, args...)); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if err := a.Activate(); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	return a, path
+}
+
+func readEntries(t *testing.T, a *appLogger, path string) []map[string]interface{} {
+	t.Helper()
+
+	if err := a.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open log file: %v", err)
+	}
+	defer f.Close()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode %s: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSlogLogger_LevelsAndFields(t *testing.T) {
+	a, path := newTestLogger(t, "-log-level", "info", "-log-levels", "gorm=warn")
+
+	ctx := logger.ContextWithFields(context.Background(), logger.Fields{"request_id": "r1"})
+
+	a.GetLogger("gorm").Info("hidden")
+	a.GetLogger("gorm").Warnf("slow query %d", 1)
+	a.GetLogger("app").Debug("hidden")
+	a.GetLogger("app").Infow("created", "user_id", 42)
+	a.GetLogger("app").InfoCtx(ctx, "handled")
+
+	entries := readEntries(t, a, path)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %v", entries)
+	}
+
+	if e := entries[0]; e["msg"] != "slow query 1" || e["prefix"] != "gorm" || e["level"] != "WARN" {
+		t.Fatalf("unexpected entry: %v", e)
+	}
+	if e := entries[1]; e["user_id"] != float64(42) || e["prefix"] != "app" {
+		t.Fatalf("unexpected entry: %v", e)
+	}
+	if e := entries[2]; e["request_id"] != "r1" {
+		t.Fatalf("unexpected entry: %v", e)
+	}
+
+	source, _ := entries[0]["source"].(map[string]interface{})
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "app_logger_test.go") {
+		t.Fatalf("expected source in test file, got %v", source)
+	}
+
+	if levels := a.GetPrefixLevels(); levels["gorm"] != "warn" {
+		t.Fatalf("unexpected prefix levels: %v", levels)
+	}
+}
+
+func TestSlogHandler_RespectsPrefixLevel(t *testing.T) {
+	a, path := newTestLogger(t, "-log-level", "info", "-log-levels", "lib=error")
+
+	l := logger.NewSlogLogger(a, "lib").With("component", "client").WithGroup("req")
+	l.Warn("hidden")
+	l.Error("request failed", "status", 500)
+
+	entries := readEntries(t, a, path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %v", entries)
+	}
+	if e := entries[0]; e["prefix"] != "lib" || e["component"] != "client" || e["req.status"] != float64(500) {
+		t.Fatalf("unexpected entry: %v", e)
+	}
+}
+
+func TestSlogLogger_AcceptsZapFlags(t *testing.T) {
+	a, path := newTestLogger(t,
+		"-log-level", "info",
+		"-log-file-max-size", "10",
+		"-log-file-max-age", "24h",
+		"-log-file-max-backups", "3",
+		"-log-sampling-initial", "0",
+		"-log-sampling-thereafter", "0",
+	)
+
+	a.GetLogger("app").Info("hello")
+
+	if entries := readEntries(t, a, path); len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+}
@@ -0,0 +1,141 @@
+package sloglogger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/DatLe328/service-context/logger"
+)
+
+type slogLogger struct {
+	handler slog.Handler
+}
+
+func (l *slogLogger) Debug(args ...interface{}) {
+	l.log(context.Background(), slog.LevelDebug, fmt.Sprint(args...))
+}
+func (l *slogLogger) Info(args ...interface{}) {
+	l.log(context.Background(), slog.LevelInfo, fmt.Sprint(args...))
+}
+func (l *slogLogger) Warn(args ...interface{}) {
+	l.log(context.Background(), slog.LevelWarn, fmt.Sprint(args...))
+}
+func (l *slogLogger) Error(args ...interface{}) {
+	l.log(context.Background(), slog.LevelError, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) Debugf(format string, args ...interface{}) {
+	l.log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+func (l *slogLogger) Infof(format string, args ...interface{}) {
+	l.log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+func (l *slogLogger) Warnf(format string, args ...interface{}) {
+	l.log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+func (l *slogLogger) Errorf(format string, args ...interface{}) {
+	l.log(context.Background(), slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.log(context.Background(), slog.LevelDebug, msg, keysAndValues...)
+}
+func (l *slogLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.log(context.Background(), slog.LevelInfo, msg, keysAndValues...)
+}
+func (l *slogLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.log(context.Background(), slog.LevelWarn, msg, keysAndValues...)
+}
+func (l *slogLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.log(context.Background(), slog.LevelError, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Fatal(args ...interface{}) {
+	l.log(context.Background(), levelFatal, fmt.Sprint(args...))
+	os.Exit(1)
+}
+func (l *slogLogger) Fatalf(format string, args ...interface{}) {
+	l.log(context.Background(), levelFatal, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+func (l *slogLogger) Panic(args ...interface{}) {
+	msg := fmt.Sprint(args...)
+	l.log(context.Background(), levelPanic, msg)
+	panic(msg)
+}
+func (l *slogLogger) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	l.log(context.Background(), levelPanic, msg)
+	panic(msg)
+}
+
+func (l *slogLogger) DebugCtx(ctx context.Context, args ...interface{}) {
+	l.log(ctx, slog.LevelDebug, fmt.Sprint(args...))
+}
+func (l *slogLogger) InfoCtx(ctx context.Context, args ...interface{}) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprint(args...))
+}
+func (l *slogLogger) WarnCtx(ctx context.Context, args ...interface{}) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprint(args...))
+}
+func (l *slogLogger) ErrorCtx(ctx context.Context, args ...interface{}) {
+	l.log(ctx, slog.LevelError, fmt.Sprint(args...))
+}
+
+func (l *slogLogger) DebugfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+func (l *slogLogger) InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+func (l *slogLogger) WarnfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+func (l *slogLogger) ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *slogLogger) With(key string, value interface{}) logger.Logger {
+	return &slogLogger{
+		handler: l.handler.WithAttrs([]slog.Attr{slog.Any(key, value)}),
+	}
+}
+
+func (l *slogLogger) WithFields(fields logger.Fields) logger.Logger {
+	return &slogLogger{
+		handler: l.handler.WithAttrs(fieldAttrs(fields)),
+	}
+}
+
+func (l *slogLogger) WithContext(ctx context.Context) logger.Logger {
+	return l.WithFields(logger.FieldsFromContext(ctx))
+}
+
+// log must be called directly by the exported methods so that the source
+// points to their caller.
+func (l *slogLogger) log(ctx context.Context, level slog.Level, msg string, keysAndValues ...interface{}) {
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip Callers, log and the exported method
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(fieldAttrs(logger.FieldsFromContext(ctx))...)
+	r.Add(keysAndValues...)
+
+	_ = l.handler.Handle(ctx, r)
+}
+
+func fieldAttrs(fields logger.Fields) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for k, v := range fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	return attrs
+}
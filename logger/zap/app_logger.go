@@ -3,95 +3,187 @@ package zaplogger
 import (
 	"errors"
 	"flag"
+	"fmt"
+	"os"
+	"time"
 
+	"github.com/DatLe328/service-context/internal/flagutil"
 	"github.com/DatLe328/service-context/logger"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 type appLogger struct {
-	level       string
-	atomicLevel zap.AtomicLevel
-	logger      *zap.Logger
+	*logger.Levels
+	prefixLevels       string
+	encoding           string
+	outputPaths        string
+	fileMaxSizeMB      int
+	fileMaxAge         time.Duration
+	fileMaxBackups     int
+	samplingInitial    int
+	samplingThereafter int
+	logger             *zap.Logger
+	files              []*rotatingFile
 }
 
 func NewZapLogger() logger.AppLogger {
 	zl, _ := zap.NewProduction()
 
 	return &appLogger{
-		level:       "",
-		atomicLevel: zap.NewAtomicLevel(),
-		logger:      zl,
+		Levels: logger.NewLevels(parseLevel, formatLevel),
+		logger: zl,
 	}
 }
 
-func (a *appLogger) InitFlags() {
-	flag.StringVar(
-		&a.level,
+func (a *appLogger) InitFlags(fs *flag.FlagSet) {
+	fs.Var(
+		a.Flag(),
 		"log-level",
-		"",
 		"Log level: debug | info | warn | error",
 	)
+	fs.StringVar(
+		&a.prefixLevels,
+		"log-levels",
+		"",
+		"Log level per logger prefix, e.g. gorm=warn,mongo=debug",
+	)
+	fs.StringVar(
+		&a.encoding,
+		"log-encoding",
+		"",
+		"Log encoding: json | console (default console when log level is debug, json otherwise)",
+	)
+	fs.StringVar(
+		&a.outputPaths,
+		"log-output-paths",
+		"stderr",
+		"Comma-separated log outputs: stdout, stderr or file paths",
+	)
+	fs.IntVar(
+		&a.fileMaxSizeMB,
+		"log-file-max-size",
+		100,
+		"Size in megabytes after which log files are rotated, 0 to disable rotation",
+	)
+	fs.DurationVar(
+		&a.fileMaxAge,
+		"log-file-max-age",
+		0,
+		"Age after which rotated log files are removed, 0 to keep them",
+	)
+	fs.IntVar(
+		&a.fileMaxBackups,
+		"log-file-max-backups",
+		0,
+		"Number of rotated log files to keep, 0 to keep all",
+	)
+	fs.IntVar(
+		&a.samplingInitial,
+		"log-sampling-initial",
+		100,
+		"Entries with the same level and message logged per second before sampling, 0 to disable sampling",
+	)
+	fs.IntVar(
+		&a.samplingThereafter,
+		"log-sampling-thereafter",
+		100,
+		"Once sampling, log every Nth entry with the same level and message",
+	)
 }
 
 func (a *appLogger) Activate() error {
-	if a.level == "" {
+	level := a.GetLevel()
+	if level == "" {
 		return errors.New("log level cannot be empty")
 	}
 
-	lv, err := zapcore.ParseLevel(a.level)
-	if err != nil {
+	if err := a.SetLevel(level); err != nil {
 		return err
 	}
+	lv := zapcore.Level(a.Level())
 
-	a.atomicLevel.SetLevel(lv)
+	if err := a.SetPrefixLevels(a.prefixLevels); err != nil {
+		return err
+	}
 
-	var cfg zap.Config
-	if lv == zap.DebugLevel {
-		cfg = zap.NewDevelopmentConfig()
-	} else {
-		cfg = zap.NewProductionConfig()
+	encoding := a.encoding
+	if encoding == "" {
+		encoding = "json"
+		if lv == zap.DebugLevel {
+			encoding = "console"
+		}
 	}
 
-	cfg.Level = a.atomicLevel
+	var encoder zapcore.Encoder
+	switch encoding {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	case "console":
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	default:
+		return fmt.Errorf("invalid log encoding: %s (allowed: json | console)", encoding)
+	}
 
-	logger, err := cfg.Build(
-		zap.AddCaller(),
-		zap.AddCallerSkip(1),
-	)
+	paths := flagutil.SplitList(a.outputPaths)
+	if len(paths) == 0 {
+		return errors.New("log output paths cannot be empty")
+	}
+
+	if a.samplingInitial < 0 || a.samplingThereafter < 0 {
+		return errors.New("log sampling values cannot be negative")
+	}
+
+	sink, files, err := openSinks(paths, rotationConfig{
+		maxSize:    int64(a.fileMaxSizeMB) << 20,
+		maxAge:     a.fileMaxAge,
+		maxBackups: a.fileMaxBackups,
+	})
 	if err != nil {
 		return err
 	}
 
-	a.logger = logger
-	return nil
+	// levels are filtered per prefix by levelFilterCore
+	core := zapcore.NewCore(encoder, sink, zapcore.DebugLevel)
+	if a.samplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, a.samplingInitial, a.samplingThereafter)
+	}
+
+	opts := []zap.Option{
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	}
+	if lv == zap.DebugLevel {
+		opts = append(opts, zap.Development(), zap.AddStacktrace(zap.WarnLevel))
+	} else {
+		opts = append(opts, zap.AddStacktrace(zap.ErrorLevel))
+	}
+
+	previous := a.files
+	a.logger = zap.New(core, opts...)
+	a.files = files
+	return closeFiles(previous)
 }
 
 func (a *appLogger) GetLogger(prefix string) logger.Logger {
+	enabler := prefixEnabler{a.Prefix(prefix)}
+
 	return &zapLogger{
 		sugar: a.logger.
+			WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+				return &levelFilterCore{Core: core, enabler: enabler}
+			})).
 			With(zap.String("prefix", prefix)).
 			Sugar(),
 	}
 }
 
-func (a *appLogger) GetLevel() string {
-	return a.level
-}
-
-func (a *appLogger) SetLevel(level string) error {
-	lv, err := zapcore.ParseLevel(level)
-	if err != nil {
-		return err
-	}
-	a.level = level
-	a.atomicLevel.SetLevel(lv)
-	return nil
-}
-
 func (a *appLogger) Stop() error {
 	if a.logger != nil {
 		_ = a.logger.Sync()
 	}
-	return nil
+	files := a.files
+	a.files = nil
+	return closeFiles(files)
 }
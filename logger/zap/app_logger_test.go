@@ -0,0 +1,256 @@
+package zaplogger
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DatLe328/service-context/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestLogger(t *testing.T, args ...string) *appLogger {
+	t.Helper()
+
+	a := NewZapLogger().(*appLogger)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	a.InitFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return a
+}
+
+func newObservedLogger(t *testing.T, level, prefixLevels string) (*appLogger, *observer.ObservedLogs) {
+	t.Helper()
+
+	a := newTestLogger(t, "-log-level", level, "-log-levels", prefixLevels)
+
+	if err := a.Activate(); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	a.logger = zap.New(core)
+	return a, logs
+}
+
+func TestPrefixLevels_FromFlag(t *testing.T) {
+	a, logs := newObservedLogger(t, "info", "gorm=warn, mongo=debug")
+
+	a.GetLogger("gorm").Info("hidden")
+	a.GetLogger("gorm").Warn("gorm warn")
+	a.GetLogger("mongo").Debug("mongo debug")
+	a.GetLogger("app").Debug("hidden")
+	a.GetLogger("app").Info("app info")
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+
+	want := []string{"gorm warn", "mongo debug", "app info"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	levels := a.GetPrefixLevels()
+	if len(levels) != 2 || levels["gorm"] != "warn" || levels["mongo"] != "debug" {
+		t.Fatalf("unexpected prefix levels: %v", levels)
+	}
+}
+
+func TestPrefixLevels_SetAndReset(t *testing.T) {
+	a, logs := newObservedLogger(t, "info", "")
+
+	// loggers created before the override must follow it
+	l := a.GetLogger("gorm")
+
+	if err := a.SetPrefixLevel("gorm", "error"); err != nil {
+		t.Fatalf("set prefix level: %v", err)
+	}
+	l.Warn("hidden")
+
+	a.ResetPrefixLevel("gorm")
+	l.Warn("visible")
+
+	if err := a.SetLevel("error"); err != nil {
+		t.Fatalf("set level: %v", err)
+	}
+	l.Warn("hidden")
+
+	if logs.Len() != 1 || logs.All()[0].Message != "visible" {
+		t.Fatalf("unexpected logs: %v", logs.All())
+	}
+
+	if err := a.SetPrefixLevel("gorm", "loud"); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
+
+func TestPrefixLevels_Invalid(t *testing.T) {
+	for _, value := range []string{"gorm", "=warn", "gorm=loud"} {
+		a := newTestLogger(t, "-log-level", "info", "-log-levels", value)
+
+		if err := a.Activate(); err == nil {
+			t.Fatalf("expected error for %q", value)
+		}
+	}
+}
+
+func TestOutput_FileWithRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	a := newTestLogger(t,
+		"-log-level", "info",
+		"-log-encoding", "json",
+		"-log-output-paths", path,
+		"-log-file-max-backups", "1",
+		"-log-sampling-initial", "0",
+	)
+	if err := a.Activate(); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	a.files[0].cfg.maxSize = 200
+
+	l := a.GetLogger("app")
+	for i := 0; i < 10; i++ {
+		l.Infof("entry %d", i)
+	}
+	if err := a.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if !strings.Contains(string(data), `"msg":"entry 9"`) {
+		t.Fatalf("expected json entries in log file, got %s", data)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "app-*.log"))
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 rotated file, got %v", backups)
+	}
+}
+
+func TestOutput_Invalid(t *testing.T) {
+	cases := [][]string{
+		{"-log-encoding", "xml"},
+		{"-log-output-paths", " , "},
+		{"-log-sampling-initial", "-1"},
+	}
+
+	for _, args := range cases {
+		a := newTestLogger(t, append([]string{"-log-level", "info"}, args...)...)
+		if err := a.Activate(); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestLogger_ContextFields(t *testing.T) {
+	a, logs := newObservedLogger(t, "info", "")
+
+	ctx := logger.ContextWithFields(context.Background(), logger.Fields{"request_id": "r1"})
+	a.GetLogger("app").InfoCtx(ctx, "handled")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if fields := entries[0].ContextMap(); fields["request_id"] != "r1" || fields["prefix"] != "app" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+}
+
+func TestLogger_StructuredAndPanic(t *testing.T) {
+	a, logs := newObservedLogger(t, "info", "")
+	l := a.GetLogger("app")
+
+	l.Infow("user created", "user_id", 42, "tenant", "t1")
+
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Message != "user created" {
+		t.Fatalf("unexpected logs: %v", entries)
+	}
+	if fields := entries[0].ContextMap(); fields["user_id"] != int64(42) || fields["tenant"] != "t1" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+		if logs.Len() != 2 || logs.All()[1].Level != zapcore.PanicLevel {
+			t.Fatalf("expected panic entry, got %v", logs.All())
+		}
+	}()
+	l.Panicf("boom %d", 1)
+}
+
+func TestOutput_RotationKeepsEveryBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	r, err := openRotatingFile(path, rotationConfig{maxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// every write rotates, usually several times per millisecond
+	for i := 0; i < 20; i++ {
+		if _, err := r.Write([]byte("0123456789\n")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "app-*.log"))
+	if len(backups) != 19 {
+		t.Fatalf("expected 19 rotated files, got %d", len(backups))
+	}
+}
+
+func TestOutput_RotationFailureKeepsLogging(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	r, err := openRotatingFile(path, rotationConfig{maxSize: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := r.Write([]byte("0123456789\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// renaming a missing file fails, the log file must be reopened anyway
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("after\n")); err == nil {
+		t.Fatal("expected the rotation error")
+	}
+	if _, err := r.Write([]byte("again\n")); err != nil {
+		t.Fatalf("write after failed rotation: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "after\nagain\n" {
+		t.Fatalf("unexpected log file content %q", data)
+	}
+}
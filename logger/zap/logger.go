@@ -1,6 +1,8 @@
 package zaplogger
 
 import (
+	"context"
+
 	"github.com/DatLe328/service-context/logger"
 	"go.uber.org/zap"
 )
@@ -35,6 +37,58 @@ func (l *zapLogger) Errorf(format string, args ...interface{}) {
 	l.sugar.Errorf(format, args...)
 }
 
+func (l *zapLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.sugar.Debugw(msg, keysAndValues...)
+}
+func (l *zapLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.sugar.Infow(msg, keysAndValues...)
+}
+func (l *zapLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.sugar.Warnw(msg, keysAndValues...)
+}
+func (l *zapLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.sugar.Errorw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Fatal(args ...interface{}) {
+	l.sugar.Fatal(args...)
+}
+func (l *zapLogger) Fatalf(format string, args ...interface{}) {
+	l.sugar.Fatalf(format, args...)
+}
+func (l *zapLogger) Panic(args ...interface{}) {
+	l.sugar.Panic(args...)
+}
+func (l *zapLogger) Panicf(format string, args ...interface{}) {
+	l.sugar.Panicf(format, args...)
+}
+
+func (l *zapLogger) DebugCtx(ctx context.Context, args ...interface{}) {
+	l.withContext(ctx).Debug(args...)
+}
+func (l *zapLogger) InfoCtx(ctx context.Context, args ...interface{}) {
+	l.withContext(ctx).Info(args...)
+}
+func (l *zapLogger) WarnCtx(ctx context.Context, args ...interface{}) {
+	l.withContext(ctx).Warn(args...)
+}
+func (l *zapLogger) ErrorCtx(ctx context.Context, args ...interface{}) {
+	l.withContext(ctx).Error(args...)
+}
+
+func (l *zapLogger) DebugfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.withContext(ctx).Debugf(format, args...)
+}
+func (l *zapLogger) InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	l.withContext(ctx).Infof(format, args...)
+}
+func (l *zapLogger) WarnfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.withContext(ctx).Warnf(format, args...)
+}
+func (l *zapLogger) ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.withContext(ctx).Errorf(format, args...)
+}
+
 func (l *zapLogger) With(key string, value interface{}) logger.Logger {
 	return &zapLogger{
 		sugar: l.sugar.With(key, value),
@@ -50,3 +104,22 @@ func (l *zapLogger) WithFields(fields logger.Fields) logger.Logger {
 		sugar: l.sugar.With(args...),
 	}
 }
+
+func (l *zapLogger) WithContext(ctx context.Context) logger.Logger {
+	return &zapLogger{
+		sugar: l.withContext(ctx),
+	}
+}
+
+func (l *zapLogger) withContext(ctx context.Context) *zap.SugaredLogger {
+	fields := logger.FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return l.sugar
+	}
+
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	return l.sugar.With(args...)
+}
@@ -0,0 +1,206 @@
+package zaplogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+type rotationConfig struct {
+	maxSize    int64 // bytes, 0 disables rotation
+	maxAge     time.Duration
+	maxBackups int
+}
+
+// rotatingFile is a log file rotated once it reaches maxSize. Rotated files
+// are renamed to name-<timestamp>.ext next to the original one and removed
+// when older than maxAge or beyond maxBackups.
+type rotatingFile struct {
+	path string
+	cfg  rotationConfig
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(path string, cfg rotationConfig) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, cfg: cfg}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rotateErr error
+	if r.cfg.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.cfg.maxSize {
+		if err := r.rotate(); err != nil {
+			rotateErr = fmt.Errorf("rotate log file %s: %w", r.path, err)
+			if r.file == nil {
+				return 0, rotateErr
+			}
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, errors.Join(rotateErr, err)
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Sync()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Close()
+}
+
+// rotate always reopens r.path, so a failed rename keeps logging to the
+// current file. r.file is nil only when reopening failed, and the next write
+// tries again.
+func (r *rotatingFile) rotate() error {
+	err := r.file.Close()
+	if err == nil {
+		err = os.Rename(r.path, r.backupName(time.Now()))
+	}
+
+	if openErr := r.open(); openErr != nil {
+		r.file = nil
+		return errors.Join(err, openErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.removeOldBackups()
+	return nil
+}
+
+// backupName moves t forward while a backup already has its name, so that
+// rotations within the same millisecond do not overwrite each other.
+func (r *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(r.path)
+
+	for {
+		name := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.path, ext), t.Format(backupTimeFormat), ext)
+		if _, err := os.Lstat(name); err != nil {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// removeOldBackups is best effort: a failure must not stop logging.
+func (r *rotatingFile) removeOldBackups() {
+	if r.cfg.maxAge <= 0 && r.cfg.maxBackups <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext) + "-"
+
+	matches, err := filepath.Glob(base + "*" + ext)
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, path := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(path, base), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, path)
+		}
+	}
+
+	// the timestamp format sorts chronologically, reversed to keep the newest
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	cutoff := time.Now().Add(-r.cfg.maxAge)
+	for i, path := range backups {
+		expired := r.cfg.maxBackups > 0 && i >= r.cfg.maxBackups
+		if !expired && r.cfg.maxAge > 0 {
+			if info, err := os.Stat(path); err == nil && info.ModTime().Before(cutoff) {
+				expired = true
+			}
+		}
+
+		if expired {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// openSinks opens every output path. "stdout" and "stderr" are the standard
+// streams, anything else is a file path.
+func openSinks(paths []string, cfg rotationConfig) (zapcore.WriteSyncer, []*rotatingFile, error) {
+	var (
+		syncers []zapcore.WriteSyncer
+		files   []*rotatingFile
+	)
+
+	for _, path := range paths {
+		switch path {
+		case "stdout":
+			syncers = append(syncers, zapcore.Lock(os.Stdout))
+		case "stderr":
+			syncers = append(syncers, zapcore.Lock(os.Stderr))
+		default:
+			f, err := openRotatingFile(path, cfg)
+			if err != nil {
+				return nil, nil, errors.Join(fmt.Errorf("open log file %s: %w", path, err), closeFiles(files))
+			}
+			files = append(files, f)
+			syncers = append(syncers, f)
+		}
+	}
+
+	return zapcore.NewMultiWriteSyncer(syncers...), files, nil
+}
+
+func closeFiles(files []*rotatingFile) error {
+	var errs []error
+	for _, f := range files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
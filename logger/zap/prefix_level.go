@@ -0,0 +1,49 @@
+package zaplogger
+
+import (
+	"github.com/DatLe328/service-context/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// prefixEnabler decides whether an entry of a prefixed logger is enabled.
+type prefixEnabler struct {
+	level *logger.PrefixLevel
+}
+
+func (e prefixEnabler) Enabled(lv zapcore.Level) bool {
+	return lv >= zapcore.Level(e.level.Level())
+}
+
+// levelFilterCore replaces the level check of the wrapped core, which is
+// built with every level enabled.
+type levelFilterCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelFilterCore) Enabled(lv zapcore.Level) bool {
+	return c.enabler.Enabled(lv)
+}
+
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{
+		Core:    c.Core.With(fields),
+		enabler: c.enabler,
+	}
+}
+
+func (c *levelFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func parseLevel(level string) (int, error) {
+	lv, err := zapcore.ParseLevel(level)
+	return int(lv), err
+}
+
+func formatLevel(level int) string {
+	return zapcore.Level(level).String()
+}
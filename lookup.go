@@ -0,0 +1,33 @@
+package sctx
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GetAs looks up a component by id and asserts it to T, which is usually one
+// of the interfaces exported by the component packages (gormc.GormComponent,
+// jwtc.TokenProvider, ...).
+func GetAs[T any](serviceCtx ServiceContext, id string) (T, error) {
+	var zero T
+
+	c, ok := serviceCtx.Get(id)
+	if !ok {
+		return zero, fmt.Errorf("cannot get component %s", id)
+	}
+
+	t, ok := c.(T)
+	if !ok {
+		return zero, fmt.Errorf("component %s has type %T, expected %s", id, c, reflect.TypeFor[T]())
+	}
+
+	return t, nil
+}
+
+func MustGetAs[T any](serviceCtx ServiceContext, id string) T {
+	t, err := GetAs[T](serviceCtx, id)
+	if err != nil {
+		panic(err.Error())
+	}
+	return t
+}
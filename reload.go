@@ -0,0 +1,223 @@
+package sctx
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrRestartRequired = errors.New("cannot be reloaded, restart required")
+
+// Reloadable is implemented by components that can apply new values of their
+// own flags while running. changed maps flag names to their new raw values;
+// the component is responsible for parsing and applying them safely. Fields
+// bound to the flags are not updated, as the component may read them
+// concurrently; Reload remembers the applied values instead.
+type Reloadable interface {
+	Reload(changed map[string]string) error
+}
+
+// Reload re-reads the config and env files and notifies components of the
+// flags whose value changed. Flags set on the command line keep their value.
+// A change that cannot be applied is reported once, until the value changes
+// again.
+func (s *serviceCtx) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if err := s.loadEnvFile(); err != nil {
+		return fmt.Errorf("reload env file %s: %w", s.envFile, err)
+	}
+
+	var fileValues map[string]string
+	if s.configFile != "" {
+		var err error
+		if fileValues, err = loadConfigFile(s.configFile); err != nil {
+			return fmt.Errorf("reload config file %s: %w", s.configFile, err)
+		}
+	}
+
+	changed := make(map[string]map[string]string)
+	sources := make(map[string]string)
+
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		if s.sources[f.Name] == SourceFlag {
+			return
+		}
+
+		value, source := f.DefValue, SourceDefault
+		if v, ok := fileValues[f.Name]; ok {
+			value, source = v, SourceFile
+		}
+		if v := os.Getenv(flagEnvKey(f.Name)); v != "" {
+			value, source = v, SourceEnv
+		}
+
+		current := s.value(f)
+
+		// an empty log level means the one derived from app-env, and the live
+		// level may be an admin override the config does not know about
+		if f.Name == "log-level" {
+			if value == "" {
+				value = s.envLogLevel()
+			}
+			current = s.configuredLogLevel()
+		}
+
+		if value == current {
+			delete(s.rejected, f.Name)
+			return
+		}
+		if rejected, ok := s.rejected[f.Name]; ok && value == rejected {
+			return
+		}
+
+		owner := s.owners[f.Name]
+		if changed[owner] == nil {
+			changed[owner] = make(map[string]string)
+		}
+		changed[owner][f.Name] = value
+		sources[f.Name] = source
+	})
+
+	var errs []error
+
+	owners := make([]string, 0, len(changed))
+	for owner := range changed {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	for _, owner := range owners {
+		values := changed[owner]
+
+		var err error
+		if owner == "" {
+			err = s.reloadOwnFlags(values)
+		} else {
+			err = s.reloadComponent(owner, values)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+			for name, value := range values {
+				s.rejected[name] = value
+			}
+			continue
+		}
+
+		for name, value := range values {
+			// the applied log level is tracked by configuredLogLevel
+			if name != "log-level" {
+				s.reloaded[name] = value
+			}
+			delete(s.rejected, name)
+			s.sources[name] = sources[name]
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reloadOwnFlags applies flags registered by the service context itself.
+// Only the log level is dynamic.
+func (s *serviceCtx) reloadOwnFlags(values map[string]string) error {
+	var errs []error
+
+	for name, value := range values {
+		if name != "log-level" {
+			errs = append(errs, fmt.Errorf("%s: %w", name, ErrRestartRequired))
+			continue
+		}
+
+		if err := s.appLogger.SetLevel(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			continue
+		}
+		s.setConfiguredLogLevel(value)
+		s.logger.Infof("log level changed to %s", value)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *serviceCtx) reloadComponent(id string, values map[string]string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r, ok := s.store[id].(Reloadable)
+	if !ok {
+		return fmt.Errorf("reload %s: %s %w", id, strings.Join(names, ", "), ErrRestartRequired)
+	}
+
+	if err := r.Reload(values); err != nil {
+		return fmt.Errorf("reload %s: %w", id, err)
+	}
+
+	s.logger.Infof("reloaded component %s: %s", id, strings.Join(names, ", "))
+	return nil
+}
+
+// watchReload reloads the configuration on SIGHUP and, when
+// app-reload-interval is set, whenever the config or env file changes.
+func (s *serviceCtx) watchReload(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if s.reloadInterval > 0 {
+		ticker := time.NewTicker(s.reloadInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	lastMod := s.configModTime()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			s.logger.Info("SIGHUP received, reloading configuration")
+		case <-tick:
+			modTime := s.configModTime()
+			if modTime.Equal(lastMod) {
+				continue
+			}
+			s.logger.Info("configuration files changed, reloading configuration")
+		}
+
+		lastMod = s.configModTime()
+		if err := s.Reload(); err != nil {
+			s.logger.Errorf("reload configuration: %v", err)
+		}
+	}
+}
+
+// configModTime returns the latest modification time of the config and env
+// files.
+func (s *serviceCtx) configModTime() time.Time {
+	var latest time.Time
+
+	for _, path := range []string{s.configFile, s.envFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,179 @@
+package sctx
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"testing"
+)
+
+type reloadableComponent struct {
+	fakeComponent
+	size     int
+	reloaded map[string]string
+}
+
+func (c *reloadableComponent) InitFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.size, "reload-size", 1, "size that can change at runtime")
+}
+
+func (c *reloadableComponent) Reload(changed map[string]string) error {
+	c.reloaded = changed
+	if v, ok := changed["reload-size"]; ok {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		c.size = size
+	}
+	return nil
+}
+
+func TestServiceCtx_Reload(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "reload-size: 10\ntest-secret: first\n")
+
+	reloadable := &reloadableComponent{fakeComponent: fakeComponent{id: "reloadable"}}
+	s := NewServiceContext(
+		WithArgs("-config-file", path),
+		WithComponent(reloadable),
+		WithComponent(&secretComponent{fakeComponent: fakeComponent{id: "secret"}}),
+	).(*serviceCtx)
+
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if reloadable.size != 10 {
+		t.Fatalf("expected size 10 from config file, got %d", reloadable.size)
+	}
+
+	if err := os.WriteFile(path, []byte("reload-size: 20\ntest-secret: second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOG_LEVEL", "error")
+
+	err := s.Reload()
+	if !errors.Is(err, ErrRestartRequired) {
+		t.Fatalf("secret component cannot reload, got %v", err)
+	}
+
+	if len(reloadable.reloaded) != 1 || reloadable.reloaded["reload-size"] != "20" {
+		t.Fatalf("unexpected reloaded values: %v", reloadable.reloaded)
+	}
+
+	if reloadable.size != 20 {
+		t.Fatalf("expected size 20 after reload, got %d", reloadable.size)
+	}
+
+	if s.LogLevel() != "error" {
+		t.Fatalf("expected log level error after reload, got %s", s.LogLevel())
+	}
+
+	if src := s.source(s.flagSet.Lookup("log-level")); src != SourceEnv {
+		t.Fatalf("expected log-level source env, got %s", src)
+	}
+}
+
+func TestServiceCtx_Reload_KeepsLogLevelOverride(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "reload-size: 10\n")
+
+	s := NewServiceContext(
+		WithArgs("-config-file", path, "-app-env", PrdEnv),
+		WithComponent(&reloadableComponent{fakeComponent: fakeComponent{id: "reloadable"}}),
+	).(*serviceCtx)
+
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if err := s.SetLogLevel("", "debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.LogLevel() != "debug" {
+		t.Fatalf("reload should keep the debug override, got %s", s.LogLevel())
+	}
+
+	if err := s.ResetLogLevel(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.LogLevel() != "warn" {
+		t.Fatalf("expected the configured warn level after reset, got %s", s.LogLevel())
+	}
+}
+
+// liveReloadable applies reloaded values to a copy of its flag-bound field.
+type liveReloadable struct {
+	fakeComponent
+	size  int
+	live  int
+	calls int
+}
+
+func (c *liveReloadable) InitFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.size, "live-size", 1, "size copied when it changes at runtime")
+}
+
+func (c *liveReloadable) Reload(changed map[string]string) error {
+	c.calls++
+	size, err := strconv.Atoi(changed["live-size"])
+	if err != nil {
+		return err
+	}
+	c.live = size
+	return nil
+}
+
+func TestServiceCtx_Reload_ReportsChangesOnce(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "live-size: 10\ntest-secret: first\n")
+
+	live := &liveReloadable{fakeComponent: fakeComponent{id: "live"}}
+	s := NewServiceContext(
+		WithArgs("-config-file", path),
+		WithComponent(live),
+		WithComponent(&secretComponent{fakeComponent: fakeComponent{id: "secret"}}),
+	).(*serviceCtx)
+
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if err := os.WriteFile(path, []byte("live-size: 20\ntest-secret: second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Reload(); !errors.Is(err, ErrRestartRequired) {
+		t.Fatalf("secret component cannot reload, got %v", err)
+	}
+
+	if err := s.Reload(); err != nil {
+		t.Fatalf("unchanged config should not be reported again, got %v", err)
+	}
+
+	if live.calls != 1 || live.live != 20 {
+		t.Fatalf("expected a single reload to 20, got %d calls and size %d", live.calls, live.live)
+	}
+
+	for _, entry := range s.ConfigEntries() {
+		if entry.Key == "live-size" && entry.Value != "20" {
+			t.Fatalf("expected live-size 20 in config entries, got %s", entry.Value)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("live-size: 20\ntest-secret: third\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Reload(); !errors.Is(err, ErrRestartRequired) {
+		t.Fatalf("a new rejected value should be reported, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package sctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Runnable is implemented by long-running components such as HTTP servers,
+// consumers or schedulers. Run must block until ctx is done or the component
+// fails, and return nil on a normal shutdown.
+type Runnable interface {
+	Run(ctx context.Context) error
+}
+
+// Run loads the service context, runs every Runnable component and blocks
+// until ctx is done, SIGINT or SIGTERM is received, or a component fails.
+// While running, the configuration is reloaded on SIGHUP.
+// All components are then stopped. The first fatal error is returned,
+// joined with any stop errors.
+// With -app-config-dump or -app-validate, Run only prints to stdout and
+// returns without loading anything.
+func (s *serviceCtx) Run(ctx context.Context) error {
+	if handled, err := s.RunConfigCommands(os.Stdout); handled {
+		return err
+	}
+
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	if err := s.LoadContext(ctx); err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reloadDone := make(chan struct{})
+	go func() {
+		defer close(reloadDone)
+		s.watchReload(runCtx)
+	}()
+
+	activated := s.activatedComponents()
+	errCh := make(chan error, len(activated))
+	var wg sync.WaitGroup
+
+	for _, c := range activated {
+		r, ok := c.(Runnable)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string, r Runnable) {
+			defer wg.Done()
+
+			s.logger.Infof("running component: %s", id)
+			if err := r.Run(runCtx); err != nil && !errors.Is(err, context.Canceled) {
+				errCh <- fmt.Errorf("run %s: %w", id, err)
+			}
+		}(c.ID(), r)
+	}
+
+	var runErr error
+
+	select {
+	case <-runCtx.Done():
+		s.logger.Info("shutdown requested")
+	case runErr = <-errCh:
+		s.logger.Errorf("%v, shutting down", runErr)
+	}
+
+	cancel()
+	s.waitRunnables(&wg)
+
+	// a reload in progress must finish before components are stopped
+	<-reloadDone
+
+	return errors.Join(runErr, s.StopContext(context.Background()))
+}
+
+// waitRunnables waits for runnable components to return, bounded by the stop
+// timeout.
+func (s *serviceCtx) waitRunnables(wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if s.stopTimeout <= 0 {
+		<-done
+		return
+	}
+
+	select {
+	case <-done:
+	case <-time.After(s.stopTimeout):
+		s.logger.Warn("timed out waiting for running components to return")
+	}
+}
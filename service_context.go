@@ -1,15 +1,19 @@
 package sctx
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/DatLe328/service-context/logger"
 	zaplogger "github.com/DatLe328/service-context/logger/zap"
-	"github.com/joho/godotenv"
 )
 
 const (
@@ -20,17 +24,25 @@ const (
 
 type Component interface {
 	ID() string
-	InitFlags()
+	InitFlags(fs *flag.FlagSet)
 	Activate(ServiceContext) error
 	Stop() error
 }
 
 type ServiceContext interface {
 	Load() error
+	LoadContext(ctx context.Context) error
 	Stop() error
+	StopContext(ctx context.Context) error
+	Run(ctx context.Context) error
+	Health(ctx context.Context) HealthReport
+	Reload() error
 
 	Logger(prefix string) logger.Logger
 	LogLevel() string
+	PrefixLogLevels() map[string]string
+	SetLogLevel(prefix, level string) error
+	ResetLogLevel(prefix string) error
 
 	EnvName() string
 	GetName() string
@@ -38,18 +50,42 @@ type ServiceContext interface {
 	MustGet(id string) interface{}
 
 	OutEnv()
+	ConfigEntries() []ConfigEntry
+	WriteConfig(w io.Writer, format string) error
+	RunConfigCommands(w io.Writer) (bool, error)
 }
 
 type serviceCtx struct {
-	name       string
-	env        string
-	components []Component
-	store      map[string]Component
-	logger     logger.Logger
+	name                  string
+	flagSet               *flag.FlagSet
+	args                  []string
+	envFile               string
+	envFileKeys           map[string]bool
+	configFile            string
+	reloadInterval        time.Duration
+	reloadMu              sync.Mutex
+	env                   string
+	startTimeout          time.Duration
+	stopTimeout           time.Duration
+	componentStartTimeout time.Duration
+	componentStopTimeout  time.Duration
+	healthTimeout         time.Duration
+	configDumpFormat      string
+	validateOnly          bool
+	components            []Component
+	mu                    sync.RWMutex
+	activated             []Component
+	store                 map[string]Component
+	sources               map[string]string
+	sensitive             map[string]bool
+	owners                map[string]string
+	logLevel              string // resolved from config, unlike admin overrides
+	appLogger             logger.AppLogger
+	logger                logger.Logger
+	reloaded              map[string]string // values applied by Reload
+	rejected              map[string]string // values Reload failed to apply
 }
 
-var defaultLogger = zaplogger.NewZapLogger()
-
 type Option func(*serviceCtx)
 
 func WithName(name string) Option {
@@ -67,45 +103,106 @@ func WithComponent(c Component) Option {
 	}
 }
 
+// WithAppLogger replaces the default zap logger, e.g. with the slog backend
+// or an in-memory logger in tests.
+func WithAppLogger(l logger.AppLogger) Option {
+	return func(s *serviceCtx) {
+		s.appLogger = l
+	}
+}
+
+// WithArgs sets the command-line arguments to parse instead of os.Args[1:],
+// minus the go test flags. Calling it with no arguments disables command-line
+// parsing.
+func WithArgs(args ...string) Option {
+	return func(s *serviceCtx) {
+		s.args = append([]string{}, args...)
+	}
+}
+
 func NewServiceContext(opts ...Option) ServiceContext {
 	s := &serviceCtx{
-		store: make(map[string]Component),
+		store:       make(map[string]Component),
+		sources:     make(map[string]string),
+		sensitive:   make(map[string]bool),
+		owners:      make(map[string]string),
+		reloaded:    make(map[string]string),
+		rejected:    make(map[string]string),
+		envFileKeys: make(map[string]bool),
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
 
+	// each context owns its logger so flags, levels and files do not leak
+	// between contexts
+	if s.appLogger == nil {
+		s.appLogger = zaplogger.NewZapLogger()
+	}
+
+	if s.args == nil {
+		s.args = commandLineArgs(os.Args[1:])
+	}
+
+	s.flagSet = flag.NewFlagSet(s.flagSetName(), flag.ExitOnError)
+
 	s.initFlags()
 	s.parseFlags()
 
 	return s
 }
 
+// commandLineArgs drops the -test.* flags go test passes to test binaries,
+// which the flag set of a service context does not define.
+func commandLineArgs(args []string) []string {
+	filtered := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-test.") || strings.HasPrefix(arg, "--test.") {
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
+
+func (s *serviceCtx) flagSetName() string {
+	if s.name != "" {
+		return s.name
+	}
+	return os.Args[0]
+}
+
 func (s *serviceCtx) initFlags() {
-	flag.StringVar(&s.env, "app-env", DevEnv, "Env for service: dev | stg | prd")
-	defaultLogger.InitFlags()
+	fs := s.flagSet
+	fs.String(configFileFlag, "", "Config file (yaml | json | toml) whose keys match flag names")
+	fs.StringVar(&s.env, "app-env", DevEnv, "Env for service: dev | stg | prd")
+	fs.DurationVar(&s.startTimeout, "app-start-timeout", time.Minute, "Maximum time to activate all components, 0 to disable")
+	fs.DurationVar(&s.stopTimeout, "app-stop-timeout", 30*time.Second, "Maximum time to stop all components, 0 to disable")
+	fs.DurationVar(&s.componentStartTimeout, "app-component-start-timeout", 0, "Maximum time to activate a single component, 0 to disable")
+	fs.DurationVar(&s.componentStopTimeout, "app-component-stop-timeout", 0, "Maximum time to stop a single component, 0 to disable")
+	fs.DurationVar(&s.healthTimeout, "app-health-timeout", 5*time.Second, "Maximum time to run all health checks, 0 to disable")
+	fs.StringVar(&s.configDumpFormat, "app-config-dump", "", "Print the resolved config (json | yaml) and exit")
+	fs.BoolVar(&s.validateOnly, "app-validate", false, "Validate the config of all components and exit")
+	fs.DurationVar(&s.reloadInterval, "app-reload-interval", 0, "Interval to poll the config and env files for changes while running, 0 to disable")
+	s.appLogger.InitFlags(fs)
 	for _, c := range s.components {
-		c.InitFlags()
+		s.initComponentFlags(c)
+		s.markSensitive(c)
 	}
 }
 
 func (s *serviceCtx) Load() error {
-	if defaultLogger.GetLevel() == "" {
-		switch s.env {
-		case DevEnv:
-			_ = defaultLogger.SetLevel("debug")
-		case StgEnv:
-			_ = defaultLogger.SetLevel("info")
-		case PrdEnv:
-			_ = defaultLogger.SetLevel("warn")
-		default:
-			log.Println("here")
-			_ = defaultLogger.SetLevel("info")
-		}
+	return s.LoadContext(context.Background())
+}
+
+func (s *serviceCtx) LoadContext(ctx context.Context) error {
+	if s.appLogger.GetLevel() == "" {
+		_ = s.appLogger.SetLevel(s.envLogLevel())
 	}
+	s.setConfiguredLogLevel(s.appLogger.GetLevel())
 
-	if err := defaultLogger.Activate(); err != nil {
+	if err := s.appLogger.Activate(); err != nil {
 		return err
 	}
 
@@ -113,31 +210,115 @@ func (s *serviceCtx) Load() error {
 	s.logger.Infof(
 		"service starting env=%s log_level=%s",
 		s.env,
-		defaultLogger.GetLevel(),
+		s.appLogger.GetLevel(),
 	)
 
-	for _, c := range s.components {
+	components, err := sortComponents(s.components, s.store)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := withOptionalTimeout(ctx, s.startTimeout)
+	defer cancel()
+
+	for _, c := range components {
 		s.logger.Infof("activating component: %s", c.ID())
-		if err := c.Activate(s); err != nil {
-			return fmt.Errorf("activate %s: %v", c.ID(), err)
+		if err := s.activateComponent(ctx, c); err != nil {
+			errs := []error{fmt.Errorf("activate %s: %w", c.ID(), err)}
+			return errors.Join(append(errs, s.rollback()...)...)
 		}
+		s.mu.Lock()
+		s.activated = append(s.activated, c)
+		s.mu.Unlock()
 	}
 	return nil
 }
 
+// rollback stops every activated component in reverse order. It does not
+// reuse the activation context, which may already be expired.
+func (s *serviceCtx) rollback() []error {
+	ctx, cancel := withOptionalTimeout(context.Background(), s.stopTimeout)
+	defer cancel()
+
+	return s.stopActivated(ctx, "rollback")
+}
+
 func (s *serviceCtx) Stop() error {
+	return s.StopContext(context.Background())
+}
+
+func (s *serviceCtx) StopContext(ctx context.Context) error {
 	s.logger.Info("stopping service context")
-	for _, c := range s.components {
-		if err := c.Stop(); err != nil {
-			return fmt.Errorf("stop %s: %v", c.ID(), err)
-		}
+
+	ctx, cancel := withOptionalTimeout(ctx, s.stopTimeout)
+	defer cancel()
+
+	// never stop components while Reload is reconfiguring them
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	errs := s.stopActivated(ctx, "stop")
+	_ = s.appLogger.Stop()
+
+	return errors.Join(errs...)
+}
+
+// configuredLogLevel is the global level from flags, files and env, or the
+// one derived from app-env, ignoring changes made with SetLogLevel.
+func (s *serviceCtx) configuredLogLevel() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.logLevel == "" {
+		return s.envLogLevel()
 	}
-	_ = defaultLogger.Stop()
-	return nil
+	return s.logLevel
+}
+
+func (s *serviceCtx) setConfiguredLogLevel(level string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logLevel = level
+}
+
+// envLogLevel is the log level used when log-level is not set.
+func (s *serviceCtx) envLogLevel() string {
+	switch s.env {
+	case DevEnv:
+		return "debug"
+	case StgEnv:
+		return "info"
+	case PrdEnv:
+		return "warn"
+	default:
+		return "info"
+	}
+}
+
+// initComponentFlags registers the flags of c and remembers that c owns
+// them, so reloaded values can be routed back to it.
+func (s *serviceCtx) initComponentFlags(c Component) {
+	known := make(map[string]bool)
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		known[f.Name] = true
+	})
+
+	c.InitFlags(s.flagSet)
+
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		if !known[f.Name] {
+			s.owners[f.Name] = c.ID()
+		}
+	})
 }
 
 func (s *serviceCtx) Logger(prefix string) logger.Logger {
-	return defaultLogger.GetLogger(prefix)
+	return s.appLogger.GetLogger(prefix)
 }
 
 func (s *serviceCtx) Get(id string) (interface{}, bool) {
@@ -154,52 +335,102 @@ func (s *serviceCtx) MustGet(id string) interface{} {
 }
 
 func (s *serviceCtx) LogLevel() string {
-	return defaultLogger.GetLevel()
+	return s.appLogger.GetLevel()
+}
+
+var ErrPrefixLevelNotSupported = errors.New("logger does not support per-prefix levels")
+
+// PrefixLogLevels returns the level overrides per logger prefix.
+func (s *serviceCtx) PrefixLogLevels() map[string]string {
+	if pl, ok := s.appLogger.(logger.PrefixLeveler); ok {
+		return pl.GetPrefixLevels()
+	}
+	return map[string]string{}
+}
+
+// SetLogLevel changes the global log level when prefix is empty, or the
+// level of the loggers created with prefix otherwise.
+func (s *serviceCtx) SetLogLevel(prefix, level string) error {
+	if prefix == "" {
+		return s.appLogger.SetLevel(level)
+	}
+
+	pl, ok := s.appLogger.(logger.PrefixLeveler)
+	if !ok {
+		return ErrPrefixLevelNotSupported
+	}
+	return pl.SetPrefixLevel(prefix, level)
+}
+
+// ResetLogLevel restores the configured level when prefix is empty, or drops
+// the override of prefix otherwise.
+func (s *serviceCtx) ResetLogLevel(prefix string) error {
+	if prefix == "" {
+		return s.appLogger.SetLevel(s.configuredLogLevel())
+	}
+
+	pl, ok := s.appLogger.(logger.PrefixLeveler)
+	if !ok {
+		return ErrPrefixLevelNotSupported
+	}
+	pl.ResetPrefixLevel(prefix)
+	return nil
 }
 
 func (s *serviceCtx) EnvName() string { return s.env }
 func (s *serviceCtx) GetName() string { return s.name }
 
+// parseFlags resolves every flag from, in increasing precedence: defaults,
+// the config file, env vars (including the .env file) and the command line.
 func (s *serviceCtx) parseFlags() {
-	envFile := os.Getenv("ENV_FILE")
-	if envFile == "" {
-		envFile = ".env"
+	s.envFile = os.Getenv("ENV_FILE")
+	if s.envFile == "" {
+		s.envFile = ".env"
 	}
 
-	if _, err := os.Stat(envFile); err == nil {
-		if err := godotenv.Load(envFile); err != nil {
-			log.Fatalf("load env file %s: %v", envFile, err)
+	if err := s.loadEnvFile(); err != nil {
+		log.Fatalf("load env file %s: %v", s.envFile, err)
+	}
+
+	s.configFile = s.configFilePath()
+	if s.configFile != "" {
+		if err := s.applyConfigFile(s.configFile); err != nil {
+			log.Fatalf("load config file %s: %v", s.configFile, err)
 		}
 	}
 
 	// parse flag to env format
-	flag.VisitAll(func(f *flag.Flag) {
-		envKey := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
-		if v := os.Getenv(envKey); v != "" {
-			flag.Set(f.Name, v)
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		if v := os.Getenv(flagEnvKey(f.Name)); v != "" {
+			if err := s.flagSet.Set(f.Name, v); err == nil {
+				s.sources[f.Name] = SourceEnv
+			}
 		}
 	})
 
-	flag.Parse()
+	_ = s.flagSet.Parse(s.args)
+
+	s.flagSet.Visit(func(f *flag.Flag) {
+		if _, ok := lookupArg(s.args, f.Name); ok {
+			s.sources[f.Name] = SourceFlag
+		}
+	})
 }
 
 func (s *serviceCtx) OutEnv() {
-	fmt.Println("Resolved environment variables:")
-
-	flag.VisitAll(func(f *flag.Flag) {
-		envKey := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+	s.writeEnv(os.Stdout)
+}
 
-		value := f.Value.String()
-		source := "default"
-		if value != f.DefValue {
-			source = "override"
-		}
+func (s *serviceCtx) writeEnv(w io.Writer) {
+	fmt.Fprintln(w, "Resolved environment variables:")
 
-		fmt.Printf(
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(
+			w,
 			"%-30s = %-20s (%s)\n    ↳ %s\n\n",
-			envKey,
-			value,
-			source,
+			flagEnvKey(f.Name),
+			s.displayValue(f.Name, s.value(f)),
+			s.source(f),
 			f.Usage,
 		)
 	})
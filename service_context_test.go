@@ -0,0 +1,321 @@
+package sctx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DatLe328/service-context/logger/loggertest"
+	sloglogger "github.com/DatLe328/service-context/logger/slog"
+)
+
+func newTestServiceCtx(components ...Component) *serviceCtx {
+	s := &serviceCtx{
+		store:     make(map[string]Component),
+		appLogger: loggertest.New(),
+	}
+
+	for _, c := range components {
+		WithComponent(c)(s)
+	}
+
+	return s
+}
+
+func TestServiceCtx_Load_RollbackOnFailure(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&fakeComponent{id: "db", events: &events},
+		&fakeComponent{id: "mongo", events: &events, stopErr: errors.New("disconnect failed")},
+		&fakeComponent{id: "repo", events: &events, activateErr: errors.New("boom")},
+	)
+
+	err := s.Load()
+	if err == nil {
+		t.Fatal("expected activation error")
+	}
+
+	for _, want := range []string{"activate repo: boom", "rollback mongo: disconnect failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should contain %q", err, want)
+		}
+	}
+
+	got := strings.Join(events, ",")
+	want := "activate db,activate mongo,activate repo,stop mongo,stop db"
+	if got != want {
+		t.Fatalf("unexpected lifecycle events:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestServiceCtx_Stop_ReverseDependencyOrder(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&fakeComponent{id: "repo", deps: []string{"db"}, events: &events},
+		&fakeComponent{id: "db", events: &events},
+	)
+
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(events, ",")
+	want := "activate db,activate repo,stop repo,stop db"
+	if got != want {
+		t.Fatalf("unexpected lifecycle events:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestServiceCtx_Load_ComponentStartTimeout(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&fakeComponent{id: "db", events: &events},
+		&fakeComponent{id: "slow", events: &events, activateFor: 100 * time.Millisecond},
+	)
+	s.componentStartTimeout = 20 * time.Millisecond
+
+	err := s.Load()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "activate slow") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if last := lastEvent(&events); last != "stop db" {
+		t.Fatalf("db should be rolled back, last event: %s", last)
+	}
+
+	// slow finishes activating after the timeout and must be stopped
+	deadline := time.Now().Add(time.Second)
+	for lastEvent(&events) != "stop slow" {
+		if time.Now().After(deadline) {
+			t.Fatalf("slow should be stopped once activated, last event: %s", lastEvent(&events))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServiceCtx_Stop_AggregatesErrors(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&fakeComponent{id: "db", events: &events, stopErr: errors.New("close failed")},
+		&fakeComponent{id: "mongo", events: &events, stopErr: errors.New("disconnect failed")},
+	)
+
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.Stop()
+	if err == nil {
+		t.Fatal("expected stop error")
+	}
+
+	for _, want := range []string{"stop db: close failed", "stop mongo: disconnect failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should contain %q", err, want)
+		}
+	}
+}
+
+type fakeRunnable struct {
+	fakeComponent
+	runErr error
+}
+
+func (f *fakeRunnable) Run(ctx context.Context) error {
+	f.record("run")
+	if f.runErr != nil {
+		return f.runErr
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestServiceCtx_Run_FailureStopsOthers(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&fakeRunnable{fakeComponent: fakeComponent{id: "http", events: &events}},
+		&fakeRunnable{fakeComponent: fakeComponent{id: "consumer", events: &events}, runErr: errors.New("broker gone")},
+	)
+
+	err := s.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "run consumer: broker gone") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := strings.Join(events[len(events)-2:], ",")
+	if got != "stop consumer,stop http" {
+		t.Fatalf("components should be stopped after failure, events: %v", events)
+	}
+}
+
+func TestServiceCtx_Run_ContextCancel(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&fakeRunnable{fakeComponent: fakeComponent{id: "http", events: &events}},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := s.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if events[len(events)-1] != "stop http" {
+		t.Fatalf("http should be stopped, events: %v", events)
+	}
+}
+
+type fakeChecker struct {
+	fakeComponent
+	checkErr error
+}
+
+func (f *fakeChecker) HealthCheck(_ context.Context) error {
+	return f.checkErr
+}
+
+func TestServiceCtx_Health(t *testing.T) {
+	s := newTestServiceCtx(
+		&fakeChecker{fakeComponent: fakeComponent{id: "db"}},
+		&fakeChecker{fakeComponent: fakeComponent{id: "mongo"}, checkErr: errors.New("no reachable servers")},
+		&fakeComponent{id: "jwt"},
+	)
+
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	report := s.Health(context.Background())
+
+	if report.Healthy() {
+		t.Fatal("report should be unhealthy")
+	}
+
+	if len(report.Components) != 2 {
+		t.Fatalf("expected 2 checked components, got %d", len(report.Components))
+	}
+
+	if report.Components["db"].Status != HealthStatusUp {
+		t.Fatalf("db should be up: %+v", report.Components["db"])
+	}
+
+	if mongo := report.Components["mongo"]; mongo.Status != HealthStatusDown || mongo.Error != "no reachable servers" {
+		t.Fatalf("mongo should be down: %+v", mongo)
+	}
+}
+
+func TestGetAs(t *testing.T) {
+	s := newTestServiceCtx(&fakeComponent{id: "db"})
+
+	if _, err := GetAs[DependentComponent](s, "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := GetAs[HealthChecker](s, "db")
+	if err == nil {
+		t.Fatal("expected type mismatch error")
+	}
+
+	want := "component db has type *sctx.fakeComponent, expected sctx.HealthChecker"
+	if err.Error() != want {
+		t.Fatalf("unexpected error:\n got: %s\nwant: %s", err, want)
+	}
+
+	if _, err := GetAs[Component](s, "cache"); err == nil {
+		t.Fatal("expected not found error")
+	}
+}
+
+func TestNewServiceContext_IsolatedFlags(t *testing.T) {
+	first := NewServiceContext(
+		WithName("first"),
+		WithArgs("-app-env", PrdEnv, "-log-level", "warn"),
+		WithComponent(&fakeComponent{id: "db"}),
+	)
+
+	second := NewServiceContext(
+		WithName("second"),
+		WithArgs("-app-env", StgEnv, "-log-level", "debug"),
+		WithComponent(&fakeComponent{id: "db"}),
+	)
+
+	if first.EnvName() != PrdEnv {
+		t.Fatalf("first env should be %s, got %s", PrdEnv, first.EnvName())
+	}
+
+	if second.EnvName() != StgEnv {
+		t.Fatalf("second env should be %s, got %s", StgEnv, second.EnvName())
+	}
+
+	if first.LogLevel() != "warn" {
+		t.Fatalf("first log level should be warn, got %s", first.LogLevel())
+	}
+
+	if second.LogLevel() != "debug" {
+		t.Fatalf("second log level should be debug, got %s", second.LogLevel())
+	}
+}
+
+func TestServiceCtx_WithAppLogger(t *testing.T) {
+	app := sloglogger.NewSlogLogger()
+
+	s := NewServiceContext(WithAppLogger(app), WithArgs("-log-level", "warn")).(*serviceCtx)
+	if s.appLogger != app {
+		t.Fatal("expected the injected app logger")
+	}
+
+	if err := s.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	defer s.Stop()
+
+	if s.LogLevel() != "warn" {
+		t.Fatalf("expected warn, got %s", s.LogLevel())
+	}
+}
+
+func TestServiceCtx_WithTestLogger(t *testing.T) {
+	app := loggertest.New()
+
+	s := NewServiceContext(
+		WithAppLogger(app),
+		WithArgs("-log-level", "info"),
+		WithComponent(&fakeComponent{id: "db"}),
+	)
+	if err := s.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	_ = s.Stop()
+
+	e := app.AssertLogged(t, loggertest.LevelInfo, "activating component: db")
+	if e.Prefix != "service-context" {
+		t.Fatalf("unexpected prefix: %s", e.Prefix)
+	}
+	app.AssertNotLogged(t, loggertest.LevelDebug, "")
+}
+
+func TestCommandLineArgs_DropsTestFlags(t *testing.T) {
+	args := commandLineArgs([]string{"-test.paniconexit0", "-app-env", PrdEnv, "-test.timeout=10m0s", "-log-level=warn"})
+
+	if got := strings.Join(args, " "); got != "-app-env prd -log-level=warn" {
+		t.Fatalf("unexpected args: %s", got)
+	}
+}
@@ -0,0 +1,111 @@
+package sctx
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Validator is implemented by components that can check their configuration
+// without opening any connection.
+type Validator interface {
+	Validate() error
+}
+
+type ConfigEntry struct {
+	Key       string `json:"key" yaml:"key"`
+	Env       string `json:"env" yaml:"env"`
+	Value     string `json:"value" yaml:"value"`
+	Default   string `json:"default" yaml:"default"`
+	Source    string `json:"source" yaml:"source"`
+	Sensitive bool   `json:"sensitive" yaml:"sensitive"`
+	Usage     string `json:"usage" yaml:"usage"`
+}
+
+// ConfigEntries returns the resolved configuration, one entry per flag.
+// Values of sensitive flags are masked.
+func (s *serviceCtx) ConfigEntries() []ConfigEntry {
+	var entries []ConfigEntry
+
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		entries = append(entries, ConfigEntry{
+			Key:       f.Name,
+			Env:       flagEnvKey(f.Name),
+			Value:     s.displayValue(f.Name, s.value(f)),
+			Default:   s.displayValue(f.Name, f.DefValue),
+			Source:    s.source(f),
+			Sensitive: s.sensitive[f.Name],
+			Usage:     f.Usage,
+		})
+	})
+
+	return entries
+}
+
+// WriteConfig writes the resolved configuration to w as json or yaml.
+func (s *serviceCtx) WriteConfig(w io.Writer, format string) error {
+	entries := s.ConfigEntries()
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "yaml":
+		data, err := yaml.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unsupported config dump format: %s (allowed: json | yaml)", format)
+	}
+}
+
+// validate runs every component validation and reports all failures at once.
+// It runs before any component is activated.
+func (s *serviceCtx) validate() error {
+	var errs []error
+
+	for _, c := range s.components {
+		v, ok := c.(Validator)
+		if !ok {
+			continue
+		}
+
+		if err := v.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("validate %s: %w", c.ID(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RunConfigCommands handles -app-config-dump and -app-validate, writing their
+// output to w. It reports whether one of them was requested, in which case the
+// caller should exit instead of loading the service. Run calls it first.
+func (s *serviceCtx) RunConfigCommands(w io.Writer) (bool, error) {
+	if s.configDumpFormat == "" && !s.validateOnly {
+		return false, nil
+	}
+
+	if s.configDumpFormat != "" {
+		if err := s.WriteConfig(w, s.configDumpFormat); err != nil {
+			return true, err
+		}
+	}
+
+	if s.validateOnly {
+		if err := s.validate(); err != nil {
+			return true, fmt.Errorf("invalid configuration:\n%v", err)
+		}
+		fmt.Fprintln(w, "configuration is valid")
+	}
+
+	return true, nil
+}
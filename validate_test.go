@@ -0,0 +1,115 @@
+package sctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type invalidComponent struct {
+	fakeComponent
+	validateErr error
+}
+
+func (c *invalidComponent) Validate() error {
+	return c.validateErr
+}
+
+func TestRunConfigCommands_DumpJSON(t *testing.T) {
+	s := NewServiceContext(
+		WithArgs("-test-secret", "hunter2", "-app-env", PrdEnv),
+		WithComponent(&secretComponent{fakeComponent: fakeComponent{id: "secret"}}),
+	).(*serviceCtx)
+	s.configDumpFormat = "json"
+
+	var buf bytes.Buffer
+	handled, err := s.RunConfigCommands(&buf)
+	if !handled || err != nil {
+		t.Fatalf("unexpected result: handled=%v err=%v", handled, err)
+	}
+
+	var entries []ConfigEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	byKey := make(map[string]ConfigEntry)
+	for _, e := range entries {
+		byKey[e.Key] = e
+	}
+
+	if e := byKey["app-env"]; e.Value != PrdEnv || e.Default != DevEnv || e.Source != SourceFlag || e.Env != "APP_ENV" {
+		t.Fatalf("unexpected app-env entry: %+v", e)
+	}
+
+	if e := byKey["test-secret"]; !e.Sensitive || e.Value != redactedValue {
+		t.Fatalf("secret should be masked: %+v", e)
+	}
+}
+
+func TestRunConfigCommands_ValidateReportsAll(t *testing.T) {
+	s := NewServiceContext(
+		WithArgs(),
+		WithComponent(&invalidComponent{fakeComponent: fakeComponent{id: "jwt"}, validateErr: errors.New("secret too short")}),
+		WithComponent(&invalidComponent{fakeComponent: fakeComponent{id: "db"}}),
+		WithComponent(&invalidComponent{fakeComponent: fakeComponent{id: "mongo"}, validateErr: errors.New("database name is required")}),
+	).(*serviceCtx)
+	s.validateOnly = true
+
+	var buf bytes.Buffer
+	handled, err := s.RunConfigCommands(&buf)
+	if !handled || err == nil {
+		t.Fatalf("unexpected result: handled=%v err=%v", handled, err)
+	}
+
+	for _, want := range []string{"validate jwt: secret too short", "validate mongo: database name is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should contain %q", err, want)
+		}
+	}
+}
+
+func TestServiceCtx_Run_ConfigCommandSkipsLoad(t *testing.T) {
+	var events []string
+
+	s := NewServiceContext(
+		WithArgs("-app-validate"),
+		WithComponent(&invalidComponent{fakeComponent: fakeComponent{id: "db", events: &events}}),
+	)
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("no component should be activated, events: %v", events)
+	}
+}
+
+func TestServiceCtx_Load_ValidatesBeforeActivating(t *testing.T) {
+	var events []string
+
+	s := newTestServiceCtx(
+		&invalidComponent{fakeComponent: fakeComponent{id: "db", events: &events}},
+		&invalidComponent{fakeComponent: fakeComponent{id: "jwt", events: &events}, validateErr: errors.New("secret too short")},
+		&invalidComponent{fakeComponent: fakeComponent{id: "mongo", events: &events}, validateErr: errors.New("database name is required")},
+	)
+
+	err := s.Load()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	for _, want := range []string{"validate jwt", "validate mongo"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should contain %q", err, want)
+		}
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("no component should be activated, events: %v", events)
+	}
+}